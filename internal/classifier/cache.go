@@ -1,35 +1,263 @@
 package classifier
 
-import "sync"
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
 
 // Cache armazena classificações para evitar chamadas repetidas à IA.
+// Quando aberto com OpenCache, as entradas são persistidas em disco (JSON Lines,
+// uma entrada por linha, a última vence) e só valem para o mesmo modelo e a
+// mesma PromptVersion, então trocar de modelo ou de prompt invalida o cache.
 type Cache struct {
 	mu    sync.RWMutex
-	items map[string]*Suggestion
+	items map[string]*CacheEntry
+	model string
+	path  string
+	file  *os.File
 }
 
-// NewCache cria um novo cache de classificações.
+// CacheEntry é uma classificação persistida.
+type CacheEntry struct {
+	Key           string     `json:"key"`
+	Model         string     `json:"model"`
+	PromptVersion int        `json:"prompt_version"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Suggestion    Suggestion `json:"suggestion"`
+}
+
+// CacheStats resume o conteúdo de um cache.
+type CacheStats struct {
+	Path      string
+	SizeBytes int64
+	Total     int
+	Valid     int
+	Stale     int
+	ByModel   map[string]int
+}
+
+// NewCache cria um novo cache de classificações apenas em memória.
 func NewCache() *Cache {
 	return &Cache{
-		items: make(map[string]*Suggestion),
+		items: make(map[string]*CacheEntry),
+	}
+}
+
+// OpenCache abre (ou cria) o cache persistente em path para o modelo dado.
+func OpenCache(path, model string) (*Cache, error) {
+	c := &Cache{
+		items: make(map[string]*CacheEntry),
+		model: model,
+		path:  path,
+	}
+
+	lines, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reescrever o arquivo quando houver muitas linhas sobrescritas ou obsoletas
+	if lines > 100 && lines > 2*len(c.items) {
+		if err := c.compact(); err != nil {
+			slog.Warn("não foi possível compactar cache", "path", path, "error", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do cache: %w", err)
+	}
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir cache: %w", err)
 	}
+
+	slog.Debug("cache de classificações carregado", "path", path, "entries", len(c.items))
+	return c, nil
 }
 
-// Get retorna uma sugestão do cache, ou nil se não existir.
-func (c *Cache) Get(key string) *Suggestion {
+// ClearCache apaga o cache persistente em path.
+func ClearCache(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("erro ao apagar cache: %w", err)
+	}
+	return nil
+}
+
+// Get retorna uma sugestão do cache, ou nil se não existir ou estiver obsoleta.
+// name é o nome atual do arquivo: a chave pelo checksum também casa com cópias
+// de outro nome, e o novo nome sugerido só vale para o arquivo que o gerou.
+func (c *Cache) Get(key, name string) *Suggestion {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.items[key]
+
+	entry := c.items[c.model+"|"+key]
+	if entry == nil || entry.PromptVersion != PromptVersion {
+		return nil
+	}
+	s := entry.Suggestion
+	if s.Filename != name {
+		s.Filename = name
+		s.SuggestedName = name
+	}
+	return &s
 }
 
-// Set armazena uma sugestão no cache.
+// Set armazena uma sugestão no cache e, se persistente, grava em disco.
 func (c *Cache) Set(key string, suggestion *Suggestion) {
+	entry := &CacheEntry{
+		Key:           key,
+		Model:         c.model,
+		PromptVersion: PromptVersion,
+		CreatedAt:     time.Now().UTC(),
+		Suggestion:    *suggestion,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[entry.Model+"|"+key] = entry
+
+	if c.file == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("erro ao serializar entrada do cache", "key", key, "error", err)
+		return
+	}
+	if _, err := c.file.Write(append(data, '\n')); err != nil {
+		slog.Warn("erro ao gravar cache em disco", "path", c.path, "error", err)
+	}
+}
+
+// Stats retorna estatísticas do cache em relação ao modelo atual.
+func (c *Cache) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := CacheStats{
+		Path:    c.path,
+		Total:   len(c.items),
+		ByModel: make(map[string]int),
+	}
+	for _, e := range c.items {
+		stats.ByModel[e.Model]++
+		if e.Model == c.model && e.PromptVersion == PromptVersion {
+			stats.Valid++
+		} else {
+			stats.Stale++
+		}
+	}
+	if c.path != "" {
+		if info, err := os.Stat(c.path); err == nil {
+			stats.SizeBytes = info.Size()
+		}
+	}
+	return stats
+}
+
+// Close fecha o arquivo do cache persistente.
+func (c *Cache) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items[key] = suggestion
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
 }
 
-// CacheKey gera uma chave de cache baseada no nome e tipo do arquivo.
-func CacheKey(name, mimeType string) string {
-	return name + "|" + mimeType
+// load lê o arquivo do cache e retorna quantas linhas ele tinha.
+func (c *Cache) load() (int, error) {
+	f, err := os.Open(c.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao abrir cache: %w", err)
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines++
+		var entry CacheEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			slog.Debug("linha inválida no cache, ignorando", "line", lines, "error", err)
+			continue
+		}
+		// Versões antigas do prompt nunca voltam a ser válidas
+		if entry.PromptVersion != PromptVersion {
+			continue
+		}
+		c.items[entry.Model+"|"+entry.Key] = &entry
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("erro ao ler cache: %w", err)
+	}
+	return lines, nil
+}
+
+// compact reescreve o arquivo apenas com as entradas em memória.
+func (c *Cache) compact() error {
+	keys := make([]string, 0, len(c.items))
+	for k := range c.items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tmp := c.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, k := range keys {
+		if err := enc.Encode(c.items[k]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	slog.Debug("cache compactado", "path", c.path, "entries", len(keys))
+	return os.Rename(tmp, c.path)
+}
+
+// CacheKey gera a chave de cache de um arquivo. Usa o checksum MD5 do conteúdo
+// quando disponível; arquivos nativos do Google (Docs, Sheets...) não têm
+// checksum e caem para nome, tamanho e tipo.
+func CacheKey(md5Checksum, name string, size int64, mimeType string) string {
+	if md5Checksum != "" {
+		return "md5:" + md5Checksum
+	}
+	return "name:" + name + "|" + strconv.FormatInt(size, 10) + "|" + mimeType
+}
+
+// ImageCacheKey gera a chave de cache da classificação por imagem, separada
+// da chave dos metadados. Só existe para arquivos com checksum.
+func ImageCacheKey(md5Checksum string) string {
+	if md5Checksum == "" {
+		return ""
+	}
+	return "image:" + md5Checksum
 }
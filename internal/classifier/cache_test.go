@@ -0,0 +1,43 @@
+package classifier
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheGetUsesCurrentName(t *testing.T) {
+	c, err := OpenCache(filepath.Join(t.TempDir(), "cache.jsonl"), "modelo")
+	if err != nil {
+		t.Fatalf("OpenCache: %v", err)
+	}
+	defer c.Close()
+
+	key := CacheKey("abc123", "scan001.pdf", 100, "application/pdf")
+	c.Set(key, &Suggestion{
+		Filename:        "scan001.pdf",
+		SuggestedFolder: "Financeiro/Boletos",
+		SuggestedName:   "Boleto Luz 2024-01.pdf",
+		Confidence:      0.9,
+	})
+
+	s := c.Get(key, "scan001.pdf")
+	if s == nil || s.SuggestedName != "Boleto Luz 2024-01.pdf" {
+		t.Fatalf("Get do mesmo arquivo = %+v, want o nome sugerido", s)
+	}
+
+	// Uma cópia de outro nome reaproveita a pasta, mas não o nome sugerido
+	s = c.Get(key, "copia do boleto.pdf")
+	if s == nil {
+		t.Fatal("Get da cópia = nil")
+	}
+	if s.Filename != "copia do boleto.pdf" || s.SuggestedName != "copia do boleto.pdf" {
+		t.Errorf("Get da cópia = %q → %q, want o nome atual", s.Filename, s.SuggestedName)
+	}
+	if s.SuggestedFolder != "Financeiro/Boletos" {
+		t.Errorf("SuggestedFolder = %q, want Financeiro/Boletos", s.SuggestedFolder)
+	}
+
+	if s := c.Get(key, "scan001.pdf"); s.SuggestedName != "Boleto Luz 2024-01.pdf" {
+		t.Errorf("Get alterou a entrada do cache: %q", s.SuggestedName)
+	}
+}
@@ -0,0 +1,156 @@
+package classifier
+
+import (
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrBudgetExceeded indica que o custo acumulado atingiu o limite (max_cost).
+var ErrBudgetExceeded = errors.New("orçamento de custo da IA atingido")
+
+// ModelPrice é o preço de um modelo em USD por milhão de tokens.
+type ModelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// DefaultPrices são os preços de referência dos modelos Gemini (USD por milhão
+// de tokens, faixa padrão). Podem ser sobrescritos pela configuração "pricing".
+// Modelos fora da tabela, como os locais via Ollama, custam zero.
+var DefaultPrices = map[string]ModelPrice{
+	"gemini-2.5-pro":        {Input: 1.25, Output: 10.00},
+	"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+	"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+	"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+	"gemini-2.0-flash-lite": {Input: 0.075, Output: 0.30},
+	"gemini-1.5-pro":        {Input: 1.25, Output: 5.00},
+	"gemini-1.5-flash":      {Input: 0.075, Output: 0.30},
+	"gemini-1.5-flash-8b":   {Input: 0.0375, Output: 0.15},
+}
+
+// ModelUsage acumula o uso e o custo de um modelo.
+type ModelUsage struct {
+	Model           string
+	Requests        int
+	PromptTokens    int64
+	CandidateTokens int64
+	Cost            float64
+}
+
+// CostTracker soma o custo das chamadas à IA e controla o orçamento.
+// É seguro para uso concorrente.
+type CostTracker struct {
+	mu      sync.Mutex
+	prices  map[string]ModelPrice
+	limit   float64
+	total   float64
+	byModel map[string]*ModelUsage
+	warned  map[string]bool
+}
+
+// NewCostTracker cria um contador com a tabela de preços e o limite em USD.
+// Um limite menor ou igual a zero desativa o orçamento.
+func NewCostTracker(prices map[string]ModelPrice, limit float64) *CostTracker {
+	return &CostTracker{
+		prices:  prices,
+		limit:   limit,
+		byModel: make(map[string]*ModelUsage),
+		warned:  make(map[string]bool),
+	}
+}
+
+// Add registra o uso de uma chamada ao modelo.
+func (t *CostTracker) Add(model string, usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	price, ok := t.price(model)
+	if !ok && !t.warned[model] {
+		t.warned[model] = true
+		slog.Debug("modelo sem preço configurado, custo considerado zero", "model", model)
+	}
+
+	cost := (float64(usage.PromptTokens)*price.Input + float64(usage.CandidateTokens)*price.Output) / 1e6
+
+	u := t.byModel[model]
+	if u == nil {
+		u = &ModelUsage{Model: model}
+		t.byModel[model] = u
+	}
+	u.Requests++
+	u.PromptTokens += usage.PromptTokens
+	u.CandidateTokens += usage.CandidateTokens
+	u.Cost += cost
+	t.total += cost
+
+	slog.Debug("uso de tokens", "model", model, "prompt", usage.PromptTokens, "candidates", usage.CandidateTokens, "cost", cost, "total", t.total)
+}
+
+// price procura o preço do modelo pelo nome exato ou pelo maior prefixo
+// conhecido (ex: "gemini-2.0-flash-001" usa "gemini-2.0-flash").
+func (t *CostTracker) price(model string) (ModelPrice, bool) {
+	model = strings.ToLower(strings.TrimPrefix(model, "models/"))
+	if p, ok := t.prices[model]; ok {
+		return p, true
+	}
+
+	best := ""
+	for name := range t.prices {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return t.prices[best], true
+}
+
+// Total retorna o custo acumulado em USD.
+func (t *CostTracker) Total() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Limit retorna o orçamento atual em USD (zero se desativado).
+func (t *CostTracker) Limit() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+// SetLimit altera o orçamento, por exemplo para continuar depois de atingi-lo.
+func (t *CostTracker) SetLimit(limit float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = limit
+}
+
+// Exceeded informa se o custo acumulado atingiu o orçamento.
+func (t *CostTracker) Exceeded() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit > 0 && t.total >= t.limit
+}
+
+// Breakdown retorna o uso por modelo, do mais caro para o mais barato.
+func (t *CostTracker) Breakdown() []ModelUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]ModelUsage, 0, len(t.byModel))
+	for _, u := range t.byModel {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cost != result[j].Cost {
+			return result[i].Cost > result[j].Cost
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
@@ -3,33 +3,168 @@ package classifier
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
+
+	"github.com/vitoramaral10/driver-organizer/internal/ratelimit"
 )
 
 // Suggestion é a sugestão de classificação da IA para um arquivo.
 type Suggestion struct {
+	Index           int     `json:"index,omitempty"`
 	Filename        string  `json:"filename"`
 	SuggestedFolder string  `json:"suggested_folder"`
 	SuggestedName   string  `json:"suggested_name"`
 	Reason          string  `json:"reason"`
 	Confidence      float64 `json:"confidence"`
 	NeedsContent    bool    `json:"needs_content"`
+
+	// Offline indica que a sugestão veio da heurística local e não da IA.
+	Offline bool `json:"-"`
+
+	// Rule é o nome da regra da configuração que gerou a sugestão, sem IA.
+	Rule string `json:"-"`
 }
 
-// Classifier é o cliente de classificação via Gemini API.
+// Classifier classifica arquivos usando um Provider de modelo de linguagem.
+// Sem provider, opera em modo offline usando apenas a heurística local.
 type Classifier struct {
+	provider Provider
+	fallback *Heuristic
+	rules    Rules
+	costs    *CostTracker
+	limiter  *ratelimit.Limiter
+
+	// mu protege a contagem de chamadas com imagem da sessão
+	mu         sync.Mutex
+	imageLimit int
+	imageCalls int
+}
+
+// New cria um classificador sobre um provider já configurado.
+func New(provider Provider) *Classifier {
+	return &Classifier{provider: provider}
+}
+
+// NewOffline cria um classificador que usa apenas a heurística local.
+func NewOffline() *Classifier {
+	return &Classifier{fallback: NewHeuristic()}
+}
+
+// EnableFallback faz o classificador recorrer à heurística local quando o
+// provider falhar, em vez de retornar o erro.
+func (c *Classifier) EnableFallback() {
+	c.fallback = NewHeuristic()
+}
+
+// SetRules define as regras da configuração, avaliadas antes da IA: arquivos
+// que casam com uma regra não são enviados ao provider.
+func (c *Classifier) SetRules(rules Rules) {
+	c.rules = rules
+}
+
+// MatchRule retorna a sugestão da primeira regra que casar com o arquivo, ou
+// nil.
+func (c *Classifier) MatchRule(file FileMetadata) *Suggestion {
+	return c.rules.Match(file)
+}
+
+// SetCostTracker passa a contabilizar o custo de cada chamada em t. Com o
+// orçamento de t atingido, as chamadas à IA retornam ErrBudgetExceeded.
+func (c *Classifier) SetCostTracker(t *CostTracker) {
+	c.costs = t
+}
+
+// SetRateLimiter faz cada chamada à IA esperar por uma permissão de l, para
+// respeitar a cota de requisições por minuto do provedor.
+func (c *Classifier) SetRateLimiter(l *ratelimit.Limiter) {
+	c.limiter = l
+}
+
+// SetImageLimit limita a n as chamadas com imagem desta sessão. Atingido o
+// limite, ClassifyImage retorna ErrImageLimit. Com n <= 0 não há limite.
+func (c *Classifier) SetImageLimit(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imageLimit = n
+}
+
+// SupportsImages informa se o provider aceita imagens.
+func (c *Classifier) SupportsImages() bool {
+	_, ok := c.provider.(ImageProvider)
+	return ok
+}
+
+// Costs retorna o contador de custo, ou nil se não houver.
+func (c *Classifier) Costs() *CostTracker {
+	return c.costs
+}
+
+// BudgetExceeded informa se o orçamento de custo foi atingido.
+func (c *Classifier) BudgetExceeded() bool {
+	return c.provider != nil && c.costs != nil && c.costs.Exceeded()
+}
+
+// NewClassifier cria um novo classificador usando a Gemini API.
+func NewClassifier(ctx context.Context, apiKey, modelName string) (*Classifier, error) {
+	provider, err := NewGeminiProvider(ctx, apiKey, modelName)
+	if err != nil {
+		return nil, err
+	}
+	return New(provider), nil
+}
+
+// ModelName retorna o nome do modelo usado pelo provider, ou "offline".
+func (c *Classifier) ModelName() string {
+	if c.provider == nil {
+		return "offline"
+	}
+	return c.provider.ModelName()
+}
+
+// Close fecha o provider.
+func (c *Classifier) Close() {
+	if c.provider != nil {
+		c.provider.Close()
+	}
+}
+
+// useFallback decide se um erro do provider (ou a ausência dele) deve ser
+// coberto pela heurística local.
+func (c *Classifier) useFallback(err error) bool {
+	// Orçamento atingido não é falha do provider: quem chama decide se continua
+	if c.fallback == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrBudgetExceeded) {
+		return false
+	}
+	if c.provider != nil {
+		slog.Warn("IA indisponível, usando classificação offline", "error", err)
+	}
+	return true
+}
+
+func (c *Classifier) offline(file FileMetadata, extra string) *Suggestion {
+	s := c.fallback.Classify(file, extra)
+	if c.provider != nil {
+		s.Reason += " (IA indisponível)"
+	}
+	return s
+}
+
+// GeminiProvider implementa Provider usando a Gemini API.
+type GeminiProvider struct {
 	client    *genai.Client
 	model     *genai.GenerativeModel
 	modelName string
 }
 
-// NewClassifier cria um novo classificador usando a Gemini API.
-func NewClassifier(ctx context.Context, apiKey, modelName string) (*Classifier, error) {
+// NewGeminiProvider cria um provider para a Gemini API.
+func NewGeminiProvider(ctx context.Context, apiKey, modelName string) (*GeminiProvider, error) {
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar cliente Gemini: %w\n\nCertifique-se de:\n1. Ter uma API key do Google AI Studio\n2. Configurar via --gemini-api-key ou DORGANIZER_GEMINI_API_KEY\n3. Obtenha em: https://aistudio.google.com/apikey", err)
@@ -54,17 +189,61 @@ func NewClassifier(ctx context.Context, apiKey, modelName string) (*Classifier,
 	model.ResponseMIMEType = "application/json"
 
 	slog.Info("classificador Gemini inicializado", "model", modelName)
-	return &Classifier{
+	return &GeminiProvider{
 		client:    client,
 		model:     model,
 		modelName: modelName,
 	}, nil
 }
 
+// ModelName retorna o nome do modelo Gemini.
+func (g *GeminiProvider) ModelName() string {
+	return g.modelName
+}
+
+// Generate envia o prompt ao Gemini e retorna o texto da resposta.
+func (g *GeminiProvider) Generate(ctx context.Context, prompt string) (string, Usage, error) {
+	return g.generate(ctx, genai.Text(prompt))
+}
+
+// GenerateWithImage envia a imagem como um genai.Blob, seguida do prompt.
+func (g *GeminiProvider) GenerateWithImage(ctx context.Context, prompt, mimeType string, image []byte) (string, Usage, error) {
+	return g.generate(ctx, genai.Blob{MIMEType: mimeType, Data: image}, genai.Text(prompt))
+}
+
+func (g *GeminiProvider) generate(ctx context.Context, parts ...genai.Part) (string, Usage, error) {
+	resp, err := g.model.GenerateContent(ctx, parts...)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	if resp == nil || len(resp.Candidates) == 0 {
+		return "", Usage{}, fmt.Errorf("resposta vazia da IA")
+	}
+
+	var usage Usage
+	if resp.UsageMetadata != nil {
+		usage.PromptTokens = int64(resp.UsageMetadata.PromptTokenCount)
+		usage.CandidateTokens = int64(resp.UsageMetadata.CandidatesTokenCount)
+	}
+
+	candidate := resp.Candidates[0]
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		return "", usage, fmt.Errorf("conteúdo vazio na resposta")
+	}
+
+	text, ok := candidate.Content.Parts[0].(genai.Text)
+	if !ok {
+		return "", usage, fmt.Errorf("resposta não é texto")
+	}
+
+	return string(text), usage, nil
+}
+
 // Close fecha o cliente.
-func (c *Classifier) Close() {
-	if c.client != nil {
-		c.client.Close()
+func (g *GeminiProvider) Close() {
+	if g.client != nil {
+		g.client.Close()
 	}
 }
 
@@ -75,25 +254,129 @@ type FileMetadata struct {
 	Size         int64  `json:"size"`
 	CreatedTime  string `json:"created_time"`
 	ModifiedTime string `json:"modified_time"`
+
+	// Folder é a pasta atual do arquivo a partir da raiz ("" na raiz), usada
+	// só pelas regras.
+	Folder string `json:"-"`
 }
 
-// ClassifyBatch classifica um lote de arquivos.
+// ClassifyBatch classifica um lote de arquivos. O resultado tem uma sugestão
+// por arquivo, na mesma ordem de files, independente da ordem da resposta.
 func (c *Classifier) ClassifyBatch(ctx context.Context, files []FileMetadata, existingFolders []string) ([]Suggestion, error) {
+	// Arquivos cobertos pelas regras não vão para a IA
+	if len(c.rules) > 0 {
+		result := make([]Suggestion, len(files))
+		var pending []FileMetadata
+		var pendingIdx []int
+		for i, f := range files {
+			if s := c.rules.Match(f); s != nil {
+				s.Index = i + 1
+				result[i] = *s
+				continue
+			}
+			pending = append(pending, f)
+			pendingIdx = append(pendingIdx, i)
+		}
+		if len(pending) == len(files) {
+			return c.classifyBatch(ctx, files, existingFolders)
+		}
+		if len(pending) > 0 {
+			suggestions, err := c.classifyBatch(ctx, pending, existingFolders)
+			if err != nil {
+				return nil, err
+			}
+			for j, i := range pendingIdx {
+				result[i] = suggestions[j]
+				result[i].Index = i + 1
+			}
+		}
+		return result, nil
+	}
+
+	return c.classifyBatch(ctx, files, existingFolders)
+}
+
+func (c *Classifier) classifyBatch(ctx context.Context, files []FileMetadata, existingFolders []string) ([]Suggestion, error) {
 	prompt := buildClassificationPrompt(files, existingFolders)
 
 	slog.Debug("enviando prompt de classificação", "files", len(files))
 
-	resp, err := c.model.GenerateContent(ctx, genai.Text(prompt))
+	suggestions, err := c.ask(ctx, prompt)
 	if err != nil {
+		if c.useFallback(err) {
+			suggestions = make([]Suggestion, 0, len(files))
+			for _, f := range files {
+				suggestions = append(suggestions, *c.offline(f, ""))
+			}
+			return suggestions, nil
+		}
 		return nil, fmt.Errorf("erro ao classificar arquivos: %w", err)
 	}
 
-	suggestions, err := parseResponse(resp)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear resposta da IA: %w", err)
+	return c.matchSuggestions(files, suggestions), nil
+}
+
+// matchSuggestions associa as sugestões da resposta aos arquivos do lote pelo
+// campo index (posição na lista do prompt). Sugestões sem index válido são
+// associadas pelo nome, e arquivos que ficaram sem sugestão caem na
+// heurística local (se habilitada) ou recebem confiança zero.
+func (c *Classifier) matchSuggestions(files []FileMetadata, suggestions []Suggestion) []Suggestion {
+	result := make([]Suggestion, len(files))
+	matched := make([]bool, len(files))
+
+	var unindexed []Suggestion
+	for _, s := range suggestions {
+		i := s.Index - 1
+		if i < 0 || i >= len(files) || matched[i] {
+			unindexed = append(unindexed, s)
+			continue
+		}
+		result[i] = s
+		matched[i] = true
 	}
 
-	return suggestions, nil
+	// Com um único arquivo não há ambiguidade
+	if len(files) == 1 && !matched[0] && len(suggestions) > 0 {
+		result[0] = suggestions[0]
+		matched[0] = true
+		unindexed = nil
+	}
+
+	for _, s := range unindexed {
+		for i, f := range files {
+			if !matched[i] && f.Name == s.Filename {
+				result[i] = s
+				matched[i] = true
+				break
+			}
+		}
+	}
+
+	for i, f := range files {
+		if !matched[i] {
+			slog.Warn("IA não retornou sugestão para o arquivo", "file", f.Name)
+			if c.fallback != nil {
+				result[i] = *c.offline(f, "")
+			} else {
+				result[i] = Suggestion{
+					SuggestedFolder: "Outros",
+					Reason:          "A IA não retornou sugestão para este arquivo",
+					Confidence:      0,
+					NeedsContent:    true,
+				}
+			}
+		}
+		result[i].Index = i + 1
+		result[i].Filename = f.Name
+		if result[i].SuggestedName == "" {
+			result[i].SuggestedName = f.Name
+		}
+	}
+
+	if len(unindexed) > 0 {
+		slog.Debug("sugestões sem index válido associadas pelo nome", "count", len(unindexed))
+	}
+	return result
 }
 
 // ClassifySingle classifica um único arquivo.
@@ -103,15 +386,6 @@ func (c *Classifier) ClassifySingle(ctx context.Context, file FileMetadata, exis
 		return nil, err
 	}
 
-	if len(suggestions) == 0 {
-		return &Suggestion{
-			Filename:        file.Name,
-			SuggestedFolder: "Outros",
-			Reason:          "Não foi possível classificar",
-			Confidence:      0,
-		}, nil
-	}
-
 	return &suggestions[0], nil
 }
 
@@ -119,42 +393,85 @@ func (c *Classifier) ClassifySingle(ctx context.Context, file FileMetadata, exis
 func (c *Classifier) ClassifyWithContent(ctx context.Context, file FileMetadata, content string, existingFolders []string) (*Suggestion, error) {
 	prompt := buildContentPrompt(file, content, existingFolders)
 
-	resp, err := c.model.GenerateContent(ctx, genai.Text(prompt))
+	suggestions, err := c.ask(ctx, prompt)
 	if err != nil {
+		if c.useFallback(err) {
+			return c.offline(file, content), nil
+		}
 		return nil, fmt.Errorf("erro ao classificar com conteúdo: %w", err)
 	}
 
-	suggestions, err := parseResponse(resp)
+	return &c.matchSuggestions([]FileMetadata{file}, suggestions)[0], nil
+}
+
+// ImageFunc obtém a imagem a enviar à IA e o tipo MIME dela.
+type ImageFunc func(ctx context.Context) (image []byte, mimeType string, err error)
+
+// ClassifyImage classifica uma imagem (foto, captura de tela...) enviando-a
+// ao modelo junto com os metadados. Conta para o limite de SetImageLimit. O
+// limite e o orçamento são conferidos antes de image ser chamada, para não
+// baixar uma imagem que não será enviada; se o download ou a chamada
+// falharem, a chamada não conta para o limite.
+func (c *Classifier) ClassifyImage(ctx context.Context, file FileMetadata, image ImageFunc, existingFolders []string) (*Suggestion, error) {
+	provider, ok := c.provider.(ImageProvider)
+	if !ok {
+		return nil, ErrNoImageSupport
+	}
+	if c.BudgetExceeded() {
+		return nil, ErrBudgetExceeded
+	}
+	if err := c.takeImageCall(); err != nil {
+		return nil, err
+	}
+
+	data, mimeType, err := image(ctx)
 	if err != nil {
+		c.releaseImageCall()
 		return nil, err
 	}
 
-	if len(suggestions) == 0 {
-		return &Suggestion{
-			Filename:        file.Name,
-			SuggestedFolder: "Outros",
-			Reason:          "Não foi possível classificar",
-			Confidence:      0,
-		}, nil
+	prompt := buildImagePrompt(file, existingFolders)
+	suggestions, err := c.request(ctx, func() (string, Usage, error) {
+		return provider.GenerateWithImage(ctx, prompt, mimeType, data)
+	})
+	if err != nil {
+		c.releaseImageCall()
+		return nil, fmt.Errorf("erro ao classificar com imagem: %w", err)
 	}
 
-	return &suggestions[0], nil
+	return &c.matchSuggestions([]FileMetadata{file}, suggestions)[0], nil
+}
+
+// takeImageCall reserva uma chamada com imagem, se o limite permitir.
+func (c *Classifier) takeImageCall() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.imageLimit > 0 && c.imageCalls >= c.imageLimit {
+		return ErrImageLimit
+	}
+	c.imageCalls++
+	return nil
+}
+
+// releaseImageCall devolve uma chamada reservada que não chegou a ser feita.
+func (c *Classifier) releaseImageCall() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imageCalls--
 }
 
 // ClassifyWithDescription classifica usando uma descrição fornecida pelo usuário.
 func (c *Classifier) ClassifyWithDescription(ctx context.Context, file FileMetadata, userDescription string, existingFolders []string) (*Suggestion, error) {
 	prompt := buildDescriptionPrompt(file, userDescription, existingFolders)
 
-	resp, err := c.model.GenerateContent(ctx, genai.Text(prompt))
+	suggestions, err := c.ask(ctx, prompt)
 	if err != nil {
+		if c.useFallback(err) {
+			return c.offline(file, userDescription), nil
+		}
 		return nil, fmt.Errorf("erro ao classificar com descrição: %w", err)
 	}
 
-	suggestions, err := parseResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
 	if len(suggestions) == 0 {
 		return &Suggestion{
 			Filename:        file.Name,
@@ -167,22 +484,64 @@ func (c *Classifier) ClassifyWithDescription(ctx context.Context, file FileMetad
 	return &suggestions[0], nil
 }
 
-func parseResponse(resp *genai.GenerateContentResponse) ([]Suggestion, error) {
-	if resp == nil || len(resp.Candidates) == 0 {
-		return nil, fmt.Errorf("resposta vazia da IA")
+// ask envia o prompt ao provider e interpreta a resposta. Sem provider, retorna
+// errOffline para que os métodos de classificação usem a heurística.
+func (c *Classifier) ask(ctx context.Context, prompt string) ([]Suggestion, error) {
+	return c.request(ctx, func() (string, Usage, error) {
+		return c.provider.Generate(ctx, prompt)
+	})
+}
+
+// request faz uma chamada ao provider respeitando o orçamento e o limitador,
+// contabiliza o custo e interpreta a resposta.
+func (c *Classifier) request(ctx context.Context, generate func() (string, Usage, error)) ([]Suggestion, error) {
+	if c.provider == nil {
+		return nil, errOffline
 	}
 
-	candidate := resp.Candidates[0]
-	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		return nil, fmt.Errorf("conteúdo vazio na resposta")
+	if c.BudgetExceeded() {
+		return nil, ErrBudgetExceeded
 	}
 
-	text, ok := candidate.Content.Parts[0].(genai.Text)
-	if !ok {
-		return nil, fmt.Errorf("resposta não é texto")
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
 	}
 
-	jsonStr := strings.TrimSpace(string(text))
+	text, usage, err := generate()
+	if c.costs != nil {
+		c.costs.Add(c.provider.ModelName(), usage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := parseSuggestions(text)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao parsear resposta da IA: %w", err)
+	}
+	return suggestions, nil
+}
+
+var (
+	errOffline = errors.New("classificador em modo offline")
+
+	// ErrImageLimit indica que o limite de chamadas com imagem da sessão
+	// (max_image_calls) foi atingido.
+	ErrImageLimit = errors.New("limite de chamadas com imagem (max_image_calls) atingido")
+
+	// ErrNoImageSupport indica um provider que não aceita imagens.
+	ErrNoImageSupport = errors.New("o provedor de IA não aceita imagens")
+)
+
+// parseSuggestions interpreta o JSON retornado pelo modelo. Aceita um array de
+// sugestões, um objeto único ou um objeto que embrulha o array (comum em
+// servidores compatíveis com OpenAI), com ou sem cercas de código markdown.
+func parseSuggestions(text string) ([]Suggestion, error) {
+	jsonStr := strings.TrimSpace(text)
+	jsonStr = strings.TrimPrefix(jsonStr, "```json")
+	jsonStr = strings.TrimPrefix(jsonStr, "```")
+	jsonStr = strings.TrimSuffix(jsonStr, "```")
+	jsonStr = strings.TrimSpace(jsonStr)
 
 	var suggestions []Suggestion
 	if err := json.Unmarshal([]byte(jsonStr), &suggestions); err != nil {
@@ -191,12 +550,29 @@ func parseResponse(resp *genai.GenerateContentResponse) ([]Suggestion, error) {
 		if err2 := json.Unmarshal([]byte(jsonStr), &single); err2 != nil {
 			return nil, fmt.Errorf("erro ao parsear JSON: %w\nResposta: %s", err, jsonStr)
 		}
+		if single.Filename != "" || single.SuggestedFolder != "" {
+			return []Suggestion{single}, nil
+		}
+
+		// Tentar parsear como objeto que embrulha o array, ex: {"files": [...]}
+		var wrapper map[string]json.RawMessage
+		if err2 := json.Unmarshal([]byte(jsonStr), &wrapper); err2 == nil {
+			for _, raw := range wrapper {
+				if err3 := json.Unmarshal(raw, &suggestions); err3 == nil && len(suggestions) > 0 {
+					return suggestions, nil
+				}
+			}
+		}
 		suggestions = []Suggestion{single}
 	}
 
 	return suggestions, nil
 }
 
+// PromptVersion identifica a versão dos prompts. Incremente ao alterar
+// systemPrompt ou os builders de prompt para invalidar o cache persistente.
+const PromptVersion = 2
+
 const systemPrompt = `Você é um assistente de organização de arquivos. Sua tarefa é analisar arquivos e sugerir a melhor pasta e nome para organizá-los.
 
 Regras:
@@ -211,6 +587,7 @@ Regras:
 9. Sempre retorne um array JSON válido
 
 Responda SEMPRE em formato JSON array com objetos contendo:
+- index: número do arquivo na lista recebida (1, 2, 3...)
 - filename: nome original do arquivo
 - suggested_folder: pasta sugerida (pode ser aninhada com /)
 - suggested_name: nome sugerido (igual ao original se já for bom, ou melhor se for genérico)
@@ -258,7 +635,7 @@ func buildContentPrompt(file FileMetadata, content string, existingFolders []str
 
 	// Limitar conteúdo a ~2000 chars para não estourar tokens
 	if len(content) > 2000 {
-		content = content[:2000] + "... [truncado]"
+		content = strings.ToValidUTF8(content[:2000], "") + "... [truncado]"
 	}
 	sb.WriteString(fmt.Sprintf("Conteúdo (primeiros caracteres):\n---\n%s\n---\n", content))
 	sb.WriteString("\nRetorne um array JSON com a classificação.")
@@ -266,6 +643,26 @@ func buildContentPrompt(file FileMetadata, content string, existingFolders []str
 	return sb.String()
 }
 
+func buildImagePrompt(file FileMetadata, existingFolders []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("Classifique o seguinte arquivo com base no nome, metadados E na imagem anexada (o próprio arquivo ou uma miniatura dele).\n\n")
+
+	if len(existingFolders) > 0 {
+		sb.WriteString("Pastas já existentes:\n")
+		for _, f := range existingFolders {
+			sb.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Arquivo: %s\nTipo: %s\nTamanho: %d bytes\nCriado: %s\n\n", file.Name, file.MimeType, file.Size, file.CreatedTime))
+	sb.WriteString("Descreva no motivo o que a imagem mostra (ex: recibo, documento, captura de tela, foto de viagem) e sugira um nome descritivo se o atual for genérico.\n")
+	sb.WriteString("\nRetorne um array JSON com a classificação.")
+
+	return sb.String()
+}
+
 func buildDescriptionPrompt(file FileMetadata, userDescription string, existingFolders []string) string {
 	var sb strings.Builder
 
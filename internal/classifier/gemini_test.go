@@ -0,0 +1,154 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeImageProvider é um fakeProvider que aceita imagens e pode falhar.
+type fakeImageProvider struct {
+	fakeProvider
+	err error
+}
+
+func (p *fakeImageProvider) GenerateWithImage(ctx context.Context, prompt, mimeType string, image []byte) (string, Usage, error) {
+	if p.err != nil {
+		return "", Usage{}, p.err
+	}
+	return `[{"suggested_folder": "Fotos", "confidence": 0.9}]`, Usage{}, nil
+}
+
+func TestClassifyImageLimit(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeImageProvider{}
+	cls := New(provider)
+	cls.SetImageLimit(1)
+	file := FileMetadata{Name: "IMG_0001.jpg", MimeType: "image/jpeg"}
+
+	fetches := 0
+	image := func(ctx context.Context) ([]byte, string, error) {
+		fetches++
+		return []byte("jpeg"), "image/jpeg", nil
+	}
+	errDownload := errors.New("falha no download")
+	failing := func(ctx context.Context) ([]byte, string, error) {
+		fetches++
+		return nil, "", errDownload
+	}
+
+	// Falhas no download e na chamada não gastam o limite
+	if _, err := cls.ClassifyImage(ctx, file, failing, nil); !errors.Is(err, errDownload) {
+		t.Fatalf("ClassifyImage com download falho = %v, want %v", err, errDownload)
+	}
+	provider.err = errors.New("erro 500")
+	if _, err := cls.ClassifyImage(ctx, file, image, nil); err == nil {
+		t.Fatal("ClassifyImage com a chamada falhando deveria retornar erro")
+	}
+	provider.err = nil
+
+	s, err := cls.ClassifyImage(ctx, file, image, nil)
+	if err != nil {
+		t.Fatalf("ClassifyImage: %v", err)
+	}
+	if s.SuggestedFolder != "Fotos" {
+		t.Errorf("SuggestedFolder = %q, want Fotos", s.SuggestedFolder)
+	}
+
+	// Com o limite atingido, a imagem nem é baixada
+	fetches = 0
+	if _, err := cls.ClassifyImage(ctx, file, image, nil); !errors.Is(err, ErrImageLimit) {
+		t.Errorf("ClassifyImage além do limite = %v, want ErrImageLimit", err)
+	}
+	if fetches != 0 {
+		t.Errorf("imagem baixada %d vezes além do limite", fetches)
+	}
+}
+
+func TestClassifyImageBudgetCheckedBeforeFetch(t *testing.T) {
+	cls := New(&fakeImageProvider{})
+	costs := NewCostTracker(map[string]ModelPrice{"fake": {Input: 1}}, 1)
+	costs.Add("fake", Usage{PromptTokens: 1_000_000})
+	cls.SetCostTracker(costs)
+
+	fetched := false
+	image := func(ctx context.Context) ([]byte, string, error) {
+		fetched = true
+		return []byte("jpeg"), "image/jpeg", nil
+	}
+	_, err := cls.ClassifyImage(context.Background(), FileMetadata{Name: "foto.jpg"}, image, nil)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("ClassifyImage = %v, want ErrBudgetExceeded", err)
+	}
+	if fetched {
+		t.Error("imagem baixada com o orçamento esgotado")
+	}
+}
+
+// textProvider responde sempre com text.
+type textProvider struct{ text string }
+
+func (p textProvider) Generate(ctx context.Context, prompt string) (string, Usage, error) {
+	return p.text, Usage{}, nil
+}
+
+func (textProvider) ModelName() string { return "fake" }
+
+func (textProvider) Close() {}
+
+func TestClassifyBatchMatchesByIndex(t *testing.T) {
+	files := []FileMetadata{
+		{Name: "boleto.pdf", MimeType: "application/pdf"},
+		{Name: "foto.jpg", MimeType: "image/jpeg"},
+		{Name: "notas.txt", MimeType: "text/plain"},
+		{Name: "contrato.pdf", MimeType: "application/pdf"},
+	}
+	// Fora de ordem, um item só com o nome, um index repetido e um arquivo
+	// sem resposta
+	response := `[
+		{"index": 2, "suggested_folder": "Fotos"},
+		{"filename": "notas.txt", "suggested_folder": "Documentos/Notas"},
+		{"index": 1, "suggested_folder": "Financeiro/Boletos", "suggested_name": "Boleto.pdf"},
+		{"index": 1, "suggested_folder": "Repetido"}
+	]`
+
+	suggestions, err := New(textProvider{response}).ClassifyBatch(context.Background(), files, nil)
+	if err != nil {
+		t.Fatalf("ClassifyBatch: %v", err)
+	}
+
+	want := []struct{ folder, name string }{
+		{"Financeiro/Boletos", "Boleto.pdf"},
+		{"Fotos", "foto.jpg"},
+		{"Documentos/Notas", "notas.txt"},
+		{"Outros", "contrato.pdf"},
+	}
+	if len(suggestions) != len(want) {
+		t.Fatalf("%d sugestões, want %d", len(suggestions), len(want))
+	}
+	for i, s := range suggestions {
+		if s.Index != i+1 || s.Filename != files[i].Name || s.SuggestedFolder != want[i].folder || s.SuggestedName != want[i].name {
+			t.Errorf("sugestão %d = %d %s → %s/%s, want %s/%s", i, s.Index, s.Filename, s.SuggestedFolder, s.SuggestedName, want[i].folder, want[i].name)
+		}
+	}
+	if suggestions[3].Confidence != 0 {
+		t.Errorf("arquivo sem resposta com confiança %v, want 0", suggestions[3].Confidence)
+	}
+}
+
+func TestClassifyBatchFallbackForMissing(t *testing.T) {
+	files := []FileMetadata{
+		{Name: "x.pdf", MimeType: "application/pdf"},
+		{Name: "Screenshot_20240110.png", MimeType: "image/png"},
+	}
+	cls := New(textProvider{`[{"index": 1, "suggested_folder": "Docs"}]`})
+	cls.EnableFallback()
+
+	suggestions, err := cls.ClassifyBatch(context.Background(), files, nil)
+	if err != nil {
+		t.Fatalf("ClassifyBatch: %v", err)
+	}
+	if suggestions[1].SuggestedFolder != "Fotos/Capturas de Tela" || !suggestions[1].Offline {
+		t.Errorf("arquivo sem resposta = %+v, want a heurística local", suggestions[1])
+	}
+}
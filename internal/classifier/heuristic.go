@@ -0,0 +1,188 @@
+package classifier
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Heuristic é um classificador local e determinístico. Mapeia padrões de nome,
+// extensões e MIME types para as categorias padrão do systemPrompt. Serve como
+// modo offline e como fallback quando o provedor de IA está indisponível.
+type Heuristic struct{}
+
+// NewHeuristic cria um classificador heurístico.
+func NewHeuristic() *Heuristic {
+	return &Heuristic{}
+}
+
+type nameRule struct {
+	pattern    *regexp.Regexp
+	folder     string
+	confidence float64
+	reason     string
+}
+
+// wordStart e wordEnd delimitam uma palavra nos padrões de nome. Ao contrário
+// de \b, tratam "_" como separador ("Pagamento_boleto.pdf") e reconhecem
+// letras acentuadas como parte da palavra.
+const (
+	wordStart = `(?:^|[^\pL\pN])`
+	wordEnd   = `(?:$|[^\pL\pN])`
+)
+
+// nameRules são avaliadas em ordem; a primeira que casar com o nome (ou com o
+// texto extra, como a descrição do usuário) vence. As confianças refletem o
+// quanto o padrão sozinho costuma acertar.
+var nameRules = []nameRule{
+	{regexp.MustCompile(`(?i)` + wordStart + `(nf-?e|nfs-?e|danfe|nota[ _-]?fiscal)` + wordEnd), "Financeiro/Notas Fiscais", 0.85, "nome indica nota fiscal"},
+	{regexp.MustCompile(`(?i)` + wordStart + `boleto`), "Financeiro/Boletos", 0.85, "nome indica boleto"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(fatura|invoice)`), "Financeiro/Faturas", 0.8, "nome indica fatura"},
+	{regexp.MustCompile(`(?i)` + wordStart + `extrato`), "Financeiro/Extratos", 0.8, "nome indica extrato bancário"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(comprovante|recibo|receipt)`), "Financeiro/Comprovantes", 0.8, "nome indica comprovante ou recibo"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(irpf|imposto[ _-]?de[ _-]?renda|darf)` + wordEnd), "Financeiro/Impostos", 0.8, "nome indica documento de imposto"},
+	{regexp.MustCompile(`(?i)(screenshot|screen[ _-]?shot|captura[ _-]de[ _-]tela)`), "Fotos/Capturas de Tela", 0.85, "nome indica captura de tela"},
+	{regexp.MustCompile(`(?i)^(img|dsc|dscn|dcim|pxl|photo|foto)[_ -]?\d`), "Fotos", 0.8, "nome no padrão de câmera"},
+	{regexp.MustCompile(`(?i)^whatsapp image`), "Fotos/WhatsApp", 0.8, "imagem recebida pelo WhatsApp"},
+	{regexp.MustCompile(`(?i)^(vid|mov|mvi)[_ -]?\d`), "Vídeos", 0.8, "nome no padrão de câmera de vídeo"},
+	{regexp.MustCompile(`(?i)^whatsapp video`), "Vídeos/WhatsApp", 0.8, "vídeo recebido pelo WhatsApp"},
+	{regexp.MustCompile(`(?i)^(whatsapp audio|ptt-)`), "Música/Áudios do WhatsApp", 0.75, "áudio recebido pelo WhatsApp"},
+	{regexp.MustCompile(`(?i)` + wordStart + `contrato`), "Documentos/Contratos", 0.75, "nome indica contrato"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(curr[ií]culo|resume|cv)` + wordEnd), "Documentos/Currículos", 0.7, "nome indica currículo"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(rg|cpf|cnh|passaporte|certid[aã]o)` + wordEnd), "Documentos/Pessoais", 0.7, "nome indica documento pessoal"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(relat[oó]rio|report)`), "Trabalho/Relatórios", 0.65, "nome indica relatório"},
+	{regexp.MustCompile(`(?i)` + wordStart + `(apostila|aula|lecture|exerc[ií]cio|prova)` + wordEnd), "Estudos", 0.6, "nome indica material de estudo"},
+	{regexp.MustCompile(`(?i)` + wordStart + `backup` + wordEnd), "Backups", 0.7, "nome indica backup"},
+}
+
+type typeRule struct {
+	folder     string
+	confidence float64
+	reason     string
+}
+
+// mimeRules mapeiam MIME types exatos; prefixos como "image/" ficam em mimePrefixRules.
+var mimeRules = map[string]typeRule{
+	"application/pdf":                          {"Documentos", 0.4, "documento PDF"},
+	"application/vnd.google-apps.document":     {"Documentos", 0.4, "documento do Google Docs"},
+	"application/vnd.google-apps.spreadsheet":  {"Documentos/Planilhas", 0.45, "planilha do Google Sheets"},
+	"application/vnd.google-apps.presentation": {"Documentos/Apresentações", 0.45, "apresentação do Google Slides"},
+	"application/vnd.google-apps.photo":        {"Fotos", 0.6, "foto"},
+	"application/vnd.google-apps.video":        {"Vídeos", 0.6, "vídeo"},
+	"application/vnd.google-apps.audio":        {"Música", 0.55, "áudio"},
+	"application/zip":                          {"Backups", 0.45, "arquivo compactado"},
+	"application/x-zip-compressed":             {"Backups", 0.45, "arquivo compactado"},
+	"application/x-rar-compressed":             {"Backups", 0.45, "arquivo compactado"},
+	"application/vnd.rar":                      {"Backups", 0.45, "arquivo compactado"},
+	"application/x-7z-compressed":              {"Backups", 0.45, "arquivo compactado"},
+	"application/gzip":                         {"Backups", 0.45, "arquivo compactado"},
+	"application/x-tar":                        {"Backups", 0.45, "arquivo compactado"},
+	"application/json":                         {"Configurações", 0.4, "arquivo de dados/configuração"},
+	"application/x-yaml":                       {"Configurações", 0.4, "arquivo de configuração"},
+}
+
+var mimePrefixRules = []struct {
+	prefix string
+	rule   typeRule
+}{
+	{"image/", typeRule{"Fotos", 0.6, "arquivo de imagem"}},
+	{"video/", typeRule{"Vídeos", 0.7, "arquivo de vídeo"}},
+	{"audio/", typeRule{"Música", 0.65, "arquivo de áudio"}},
+}
+
+// extensionRules cobrem arquivos com MIME genérico (ex: application/octet-stream).
+var extensionRules = map[string]typeRule{
+	".jpg": {"Fotos", 0.6, "extensão de imagem"}, ".jpeg": {"Fotos", 0.6, "extensão de imagem"},
+	".png": {"Fotos", 0.55, "extensão de imagem"}, ".heic": {"Fotos", 0.65, "extensão de foto"},
+	".gif": {"Fotos", 0.5, "extensão de imagem"}, ".webp": {"Fotos", 0.5, "extensão de imagem"},
+	".raw": {"Fotos", 0.6, "extensão de foto"}, ".cr2": {"Fotos", 0.65, "extensão de foto"},
+	".mp4": {"Vídeos", 0.7, "extensão de vídeo"}, ".mov": {"Vídeos", 0.7, "extensão de vídeo"},
+	".mkv": {"Vídeos", 0.7, "extensão de vídeo"}, ".avi": {"Vídeos", 0.7, "extensão de vídeo"},
+	".mp3": {"Música", 0.65, "extensão de áudio"}, ".flac": {"Música", 0.65, "extensão de áudio"},
+	".wav": {"Música", 0.6, "extensão de áudio"}, ".m4a": {"Música", 0.6, "extensão de áudio"},
+	".ogg": {"Música", 0.55, "extensão de áudio"}, ".opus": {"Música", 0.55, "extensão de áudio"},
+	".pdf": {"Documentos", 0.4, "extensão de documento"}, ".doc": {"Documentos", 0.45, "extensão de documento"},
+	".docx": {"Documentos", 0.45, "extensão de documento"}, ".odt": {"Documentos", 0.45, "extensão de documento"},
+	".txt": {"Documentos", 0.35, "extensão de texto"}, ".md": {"Documentos", 0.35, "extensão de texto"},
+	".rtf": {"Documentos", 0.4, "extensão de documento"},
+	".xls": {"Documentos/Planilhas", 0.45, "extensão de planilha"}, ".xlsx": {"Documentos/Planilhas", 0.45, "extensão de planilha"},
+	".ods": {"Documentos/Planilhas", 0.45, "extensão de planilha"}, ".csv": {"Documentos/Planilhas", 0.4, "extensão de planilha"},
+	".ppt": {"Documentos/Apresentações", 0.45, "extensão de apresentação"}, ".pptx": {"Documentos/Apresentações", 0.45, "extensão de apresentação"},
+	".odp": {"Documentos/Apresentações", 0.45, "extensão de apresentação"},
+	".zip": {"Backups", 0.45, "extensão de arquivo compactado"}, ".rar": {"Backups", 0.45, "extensão de arquivo compactado"},
+	".7z": {"Backups", 0.45, "extensão de arquivo compactado"}, ".gz": {"Backups", 0.45, "extensão de arquivo compactado"},
+	".tar": {"Backups", 0.45, "extensão de arquivo compactado"}, ".bak": {"Backups", 0.6, "extensão de backup"},
+	".json": {"Configurações", 0.4, "extensão de dados/configuração"}, ".yaml": {"Configurações", 0.45, "extensão de configuração"},
+	".yml": {"Configurações", 0.45, "extensão de configuração"}, ".ini": {"Configurações", 0.5, "extensão de configuração"},
+	".conf": {"Configurações", 0.5, "extensão de configuração"}, ".env": {"Configurações", 0.5, "extensão de configuração"},
+	".go": {"Projetos", 0.45, "código-fonte"}, ".py": {"Projetos", 0.45, "código-fonte"},
+	".js": {"Projetos", 0.45, "código-fonte"}, ".ts": {"Projetos", 0.45, "código-fonte"},
+	".java": {"Projetos", 0.45, "código-fonte"}, ".dwg": {"Projetos/CAD", 0.6, "desenho CAD"},
+	".psd": {"Projetos/Design", 0.55, "arquivo de design"}, ".fig": {"Projetos/Design", 0.55, "arquivo de design"},
+}
+
+// Classify sugere uma pasta para o arquivo. extra é um texto opcional (descrição
+// do usuário ou trecho do conteúdo) avaliado com as mesmas regras de nome.
+func (h *Heuristic) Classify(file FileMetadata, extra string) *Suggestion {
+	s := &Suggestion{
+		Filename:      file.Name,
+		SuggestedName: file.Name,
+		Offline:       true,
+	}
+
+	typeMatch, hasType := matchType(file)
+
+	for _, r := range nameRules {
+		if !r.pattern.MatchString(file.Name) && (extra == "" || !r.pattern.MatchString(extra)) {
+			continue
+		}
+
+		s.SuggestedFolder = r.folder
+		s.Reason = "Heurística: " + r.reason
+		s.Confidence = r.confidence
+
+		// Nome e tipo apontando para a mesma categoria reforçam a sugestão
+		if hasType && topLevel(typeMatch.folder) == topLevel(r.folder) {
+			s.Confidence = min(s.Confidence+0.1, 0.95)
+			s.Reason += " e " + typeMatch.reason
+		}
+		return s
+	}
+
+	if hasType {
+		s.SuggestedFolder = typeMatch.folder
+		s.Reason = "Heurística: " + typeMatch.reason
+		s.Confidence = typeMatch.confidence
+		// Documentos genéricos quase sempre pedem uma subpasta melhor
+		s.NeedsContent = typeMatch.confidence < 0.5
+		return s
+	}
+
+	s.SuggestedFolder = "Outros"
+	s.Reason = "Heurística: nenhum padrão conhecido de nome, tipo ou extensão"
+	s.Confidence = 0.1
+	s.NeedsContent = true
+	return s
+}
+
+func matchType(file FileMetadata) (typeRule, bool) {
+	if r, ok := mimeRules[file.MimeType]; ok {
+		return r, true
+	}
+	for _, p := range mimePrefixRules {
+		if strings.HasPrefix(file.MimeType, p.prefix) {
+			return p.rule, true
+		}
+	}
+	if r, ok := extensionRules[strings.ToLower(path.Ext(file.Name))]; ok {
+		return r, true
+	}
+	return typeRule{}, false
+}
+
+func topLevel(folder string) string {
+	if i := strings.Index(folder, "/"); i >= 0 {
+		return folder[:i]
+	}
+	return folder
+}
@@ -0,0 +1,47 @@
+package classifier
+
+import "testing"
+
+func TestHeuristicNameRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		mime   string
+		folder string
+	}{
+		{"NF-e_12345.pdf", "application/pdf", "Financeiro/Notas Fiscais"},
+		{"NFe 12345.pdf", "application/pdf", "Financeiro/Notas Fiscais"},
+		{"nota_fiscal_jan.pdf", "application/pdf", "Financeiro/Notas Fiscais"},
+		{"Nota Fiscal - Janeiro.pdf", "application/pdf", "Financeiro/Notas Fiscais"},
+		{"danfe-0001.pdf", "application/pdf", "Financeiro/Notas Fiscais"},
+		{"Pagamento_boleto.pdf", "application/pdf", "Financeiro/Boletos"},
+		{"pagamento boleto.pdf", "application/pdf", "Financeiro/Boletos"},
+		{"pagamento-boleto.pdf", "application/pdf", "Financeiro/Boletos"},
+		{"conta_fatura_março.pdf", "application/pdf", "Financeiro/Faturas"},
+		{"Extrato_2024-01.pdf", "application/pdf", "Financeiro/Extratos"},
+		{"2024_comprovante_pix.png", "image/png", "Financeiro/Comprovantes"},
+		{"IRPF_2024.pdf", "application/pdf", "Financeiro/Impostos"},
+		{"meu_contrato_aluguel.pdf", "application/pdf", "Documentos/Contratos"},
+		{"Currículo_João.pdf", "application/pdf", "Documentos/Currículos"},
+		{"cv-2024.pdf", "application/pdf", "Documentos/Currículos"},
+		{"scan_RG.jpg", "image/jpeg", "Documentos/Pessoais"},
+		{"Relatório_Final.docx", "application/octet-stream", "Trabalho/Relatórios"},
+		{"prova_calculo.pdf", "application/pdf", "Estudos"},
+		{"site_backup.zip", "application/zip", "Backups"},
+		{"Screenshot_20240110.png", "image/png", "Fotos/Capturas de Tela"},
+
+		// Palavras que só contêm o padrão não casam
+		{"cvs_export.csv", "text/csv", "Documentos/Planilhas"},
+		{"cargo.txt", "text/plain", "Documentos"},
+		{"aprovação.pdf", "application/pdf", "Documentos"},
+	}
+
+	h := NewHeuristic()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := h.Classify(FileMetadata{Name: tt.name, MimeType: tt.mime}, "")
+			if s.SuggestedFolder != tt.folder {
+				t.Errorf("Classify(%q) = %s (%s), want %s", tt.name, s.SuggestedFolder, s.Reason, tt.folder)
+			}
+		})
+	}
+}
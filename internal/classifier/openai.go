@@ -0,0 +1,152 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProvider implementa Provider sobre qualquer endpoint compatível com a
+// API de chat completions da OpenAI, como Ollama, llama.cpp server ou vLLM.
+type OpenAIProvider struct {
+	baseURL    string
+	apiKey     string
+	modelName  string
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider cria um provider para um endpoint compatível com OpenAI.
+// baseURL deve apontar para a raiz da API, ex: "http://localhost:11434/v1".
+// apiKey é opcional para servidores locais.
+func NewOpenAIProvider(baseURL, apiKey, modelName string) (*OpenAIProvider, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("URL do endpoint compatível com OpenAI não configurada (openai_base_url)")
+	}
+	if modelName == "" {
+		return nil, fmt.Errorf("modelo não configurado (openai_model)")
+	}
+
+	slog.Info("classificador OpenAI-compatível inicializado", "model", modelName, "url", baseURL)
+	return &OpenAIProvider{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiKey:    apiKey,
+		modelName: modelName,
+		// Modelos locais podem demorar bastante para responder
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// ModelName retorna o nome do modelo.
+func (o *OpenAIProvider) ModelName() string {
+	return o.modelName
+}
+
+// Close não tem recursos a liberar; existe para satisfazer Provider.
+func (o *OpenAIProvider) Close() {}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	Temperature    float64        `json:"temperature"`
+	TopP           float64        `json:"top_p"`
+	MaxTokens      int            `json:"max_tokens"`
+	ResponseFormat responseFormat `json:"response_format"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+// jsonObjectHint complementa o systemPrompt: no modo json_object a resposta
+// precisa ser um objeto, então o array vai embrulhado (ver parseSuggestions).
+const jsonObjectHint = `
+
+Como a resposta deve ser um objeto JSON, embrulhe o array em {"suggestions": [...]}.`
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Generate envia o prompt para /chat/completions e retorna o texto da resposta.
+func (o *OpenAIProvider) Generate(ctx context.Context, prompt string) (string, Usage, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: o.modelName,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt + jsonObjectHint},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.2,
+		TopP:        0.8,
+		MaxTokens:   4096,
+		// Força JSON válido nos servidores que suportam (OpenAI, Ollama, vLLM)
+		ResponseFormat: responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("erro ao montar requisição: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("erro ao montar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("erro ao chamar %s: %w", o.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", Usage{}, fmt.Errorf("endpoint retornou %s: %s", resp.Status, strings.TrimSpace(string(data)))
+		}
+		return "", Usage{}, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Error != nil && parsed.Error.Message != "" {
+			return "", Usage{}, fmt.Errorf("endpoint retornou %s: %s", resp.Status, parsed.Error.Message)
+		}
+		return "", Usage{}, fmt.Errorf("endpoint retornou %s", resp.Status)
+	}
+
+	if len(parsed.Choices) == 0 || parsed.Choices[0].Message.Content == "" {
+		return "", Usage{}, fmt.Errorf("resposta vazia da IA")
+	}
+
+	usage := Usage{
+		PromptTokens:    parsed.Usage.PromptTokens,
+		CandidateTokens: parsed.Usage.CompletionTokens,
+	}
+	return parsed.Choices[0].Message.Content, usage, nil
+}
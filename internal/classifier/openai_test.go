@@ -0,0 +1,90 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIGenerate(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("requisição = %s %s, want POST /v1/chat/completions", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer chave" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("corpo inválido: %v", err)
+		}
+		w.Write([]byte(`{
+			"choices": [{"message": {"role": "assistant", "content": "{\"suggestions\": [{\"index\": 1, \"suggested_folder\": \"Financeiro\"}]}"}}],
+			"usage": {"prompt_tokens": 120, "completion_tokens": 30, "total_tokens": 150}
+		}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewOpenAIProvider(srv.URL+"/v1/", "chave", "llama3.1")
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider: %v", err)
+	}
+	text, usage, err := p.Generate(context.Background(), "classifique")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if got.Model != "llama3.1" || got.ResponseFormat.Type != "json_object" || got.MaxTokens != 4096 {
+		t.Errorf("requisição = modelo %q, response_format %q, max_tokens %d", got.Model, got.ResponseFormat.Type, got.MaxTokens)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Role != "user" || got.Messages[1].Content != "classifique" {
+		t.Errorf("mensagens = %+v", got.Messages)
+	}
+	if !strings.Contains(got.Messages[0].Content, "JSON") {
+		t.Error("o modo json_object exige \"JSON\" nas mensagens")
+	}
+	if usage.PromptTokens != 120 || usage.CandidateTokens != 30 {
+		t.Errorf("usage = %+v, want 120 e 30", usage)
+	}
+
+	suggestions, err := parseSuggestions(text)
+	if err != nil || len(suggestions) != 1 || suggestions[0].SuggestedFolder != "Financeiro" {
+		t.Errorf("parseSuggestions(%s) = %+v, %v", text, suggestions, err)
+	}
+}
+
+func TestOpenAIGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"erro da API", http.StatusBadRequest, `{"error": {"message": "modelo não encontrado"}}`, "modelo não encontrado"},
+		{"corpo que não é JSON", http.StatusBadGateway, "bad gateway", "bad gateway"},
+		{"sem escolhas", http.StatusOK, `{"choices": []}`, "resposta vazia"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			p, err := NewOpenAIProvider(srv.URL, "", "llama3.1")
+			if err != nil {
+				t.Fatalf("NewOpenAIProvider: %v", err)
+			}
+			if _, _, err := p.Generate(context.Background(), "x"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate = %v, want erro com %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// PrefetchItem é um arquivo a ser classificado pelo Prefetcher.
+type PrefetchItem struct {
+	File     FileMetadata
+	CacheKey string
+}
+
+type prefetchResult struct {
+	suggestion *Suggestion
+	err        error
+}
+
+// Prefetcher classifica arquivos em lotes, em segundo plano, à frente de quem
+// consome as sugestões. Arquivos no cache não vão para a IA. Só são buscados
+// os lotes dentro da janela de ahead lotes após o último arquivo pedido em Get,
+// para não gastar com arquivos que talvez nunca sejam revisados. Se o
+// orçamento de custo for atingido, a busca pausa até Resume.
+type Prefetcher struct {
+	cls       *Classifier
+	cache     *Cache
+	items     []PrefetchItem
+	batchSize int
+	ahead     int
+
+	results []chan prefetchResult
+
+	mu       sync.Mutex
+	folders  []string
+	consumed int
+	wake     chan struct{}
+	paused   chan struct{} // fechado enquanto a busca está pausada
+	resume   chan struct{}
+}
+
+// NewPrefetcher inicia a classificação em segundo plano de items. A goroutine
+// termina quando todos os lotes forem classificados ou ctx for cancelado.
+func NewPrefetcher(ctx context.Context, cls *Classifier, cache *Cache, items []PrefetchItem, existingFolders []string, batchSize, ahead int) *Prefetcher {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if ahead < 1 {
+		ahead = 1
+	}
+
+	p := &Prefetcher{
+		cls:       cls,
+		cache:     cache,
+		items:     items,
+		batchSize: batchSize,
+		ahead:     ahead,
+		results:   make([]chan prefetchResult, len(items)),
+		folders:   slices.Clone(existingFolders),
+		wake:      make(chan struct{}, 1),
+		paused:    make(chan struct{}),
+		resume:    make(chan struct{}, 1),
+	}
+	for i := range p.results {
+		p.results[i] = make(chan prefetchResult, 1)
+	}
+
+	go p.run(ctx)
+	return p
+}
+
+// Get retorna a sugestão do item i, esperando o lote dele se necessário.
+// Retorna ErrBudgetExceeded se o item ainda não foi classificado e a busca
+// está pausada pelo orçamento.
+func (p *Prefetcher) Get(ctx context.Context, i int) (*Suggestion, error) {
+	p.mu.Lock()
+	if i > p.consumed {
+		p.consumed = i
+	}
+	paused := p.paused
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case r := <-p.results[i]:
+		return r.suggestion, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-paused:
+		select {
+		case r := <-p.results[i]:
+			return r.suggestion, r.err
+		default:
+			return nil, ErrBudgetExceeded
+		}
+	}
+}
+
+// Resume retoma a busca pausada pelo orçamento, depois que ele foi aumentado.
+// A pausa é desfeita aqui, e não na goroutine da busca, para que um Get logo
+// em seguida espere o lote em vez de ver a pausa antiga.
+func (p *Prefetcher) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.paused:
+	default:
+		return
+	}
+	p.paused = make(chan struct{})
+
+	select {
+	case p.resume <- struct{}{}:
+	default:
+	}
+}
+
+// AddFolder informa uma pasta nova, usada nos prompts dos próximos lotes.
+func (p *Prefetcher) AddFolder(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !slices.Contains(p.folders, name) {
+		p.folders = append(p.folders, name)
+	}
+}
+
+func (p *Prefetcher) run(ctx context.Context) {
+	for start := 0; start < len(p.items); start += p.batchSize {
+		if !p.waitWindow(ctx, start) {
+			return
+		}
+
+		end := min(start+p.batchSize, len(p.items))
+		var pending []int
+		for i := start; i < end; i++ {
+			// Regras têm precedência sobre sugestões da IA já em cache
+			if s := p.cls.MatchRule(p.items[i].File); s != nil {
+				p.results[i] <- prefetchResult{suggestion: s}
+				continue
+			}
+			if s := p.cache.Get(p.items[i].CacheKey, p.items[i].File.Name); s != nil {
+				p.results[i] <- prefetchResult{suggestion: s}
+				continue
+			}
+			pending = append(pending, i)
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		files := make([]FileMetadata, len(pending))
+		for j, i := range pending {
+			files[j] = p.items[i].File
+		}
+
+		var suggestions []Suggestion
+		var err error
+		for {
+			p.mu.Lock()
+			folders := slices.Clone(p.folders)
+			p.mu.Unlock()
+
+			suggestions, err = p.cls.ClassifyBatch(ctx, files, folders)
+			if !errors.Is(err, ErrBudgetExceeded) {
+				break
+			}
+			if !p.pause(ctx) {
+				return
+			}
+		}
+
+		for j, i := range pending {
+			if err != nil {
+				p.results[i] <- prefetchResult{err: err}
+				continue
+			}
+			s := suggestions[j]
+			if !s.Offline && s.Rule == "" {
+				p.cache.Set(p.items[i].CacheKey, &s)
+			}
+			p.results[i] <- prefetchResult{suggestion: &s}
+		}
+	}
+}
+
+// pause sinaliza a pausa para Get e espera Resume.
+func (p *Prefetcher) pause(ctx context.Context) bool {
+	p.mu.Lock()
+	close(p.paused)
+	p.mu.Unlock()
+
+	slog.Debug("classificação em segundo plano pausada: orçamento atingido")
+
+	select {
+	case <-p.resume:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitWindow espera até que o lote iniciado em start esteja dentro da janela.
+func (p *Prefetcher) waitWindow(ctx context.Context, start int) bool {
+	for {
+		p.mu.Lock()
+		inWindow := start <= p.consumed+p.ahead*p.batchSize
+		p.mu.Unlock()
+		if inWindow {
+			return true
+		}
+
+		select {
+		case <-p.wake:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
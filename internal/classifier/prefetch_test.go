@@ -0,0 +1,52 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeProvider responde sem sugestões e cobra 1 milhão de tokens por chamada.
+type fakeProvider struct{}
+
+func (fakeProvider) Generate(ctx context.Context, prompt string) (string, Usage, error) {
+	return "[]", Usage{PromptTokens: 1_000_000}, nil
+}
+
+func (fakeProvider) ModelName() string { return "fake" }
+
+func (fakeProvider) Close() {}
+
+func TestPrefetcherResumeAfterBudget(t *testing.T) {
+	// Repetido para pegar a corrida entre Resume e a goroutine da busca
+	for run := range 50 {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		costs := NewCostTracker(map[string]ModelPrice{"fake": {Input: 1}}, 1)
+		cls := New(fakeProvider{})
+		cls.SetCostTracker(costs)
+
+		items := make([]PrefetchItem, 4)
+		for i := range items {
+			name := fmt.Sprintf("arquivo%d.bin", i)
+			items[i] = PrefetchItem{File: FileMetadata{Name: name}, CacheKey: "name:" + name}
+		}
+		pf := NewPrefetcher(ctx, cls, NewCache(), items, nil, 2, 4)
+
+		// O primeiro lote esgota o orçamento; o segundo fica pausado
+		if _, err := pf.Get(ctx, 0); err != nil {
+			t.Fatalf("run %d: Get(0): %v", run, err)
+		}
+		if _, err := pf.Get(ctx, 2); !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("run %d: Get(2) = %v, want ErrBudgetExceeded", run, err)
+		}
+
+		costs.SetLimit(10)
+		pf.Resume()
+		if _, err := pf.Get(ctx, 2); err != nil {
+			t.Fatalf("run %d: Get(2) depois de Resume: %v", run, err)
+		}
+		cancel()
+	}
+}
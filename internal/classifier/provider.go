@@ -0,0 +1,33 @@
+package classifier
+
+import "context"
+
+// Provider é um modelo de linguagem capaz de responder aos prompts de
+// classificação. O prompt de sistema (systemPrompt) é configurado pelo próprio
+// provider na criação; Generate recebe apenas o prompt do usuário e retorna o
+// texto bruto da resposta, que deve ser JSON, junto com os tokens consumidos.
+type Provider interface {
+	// Generate envia um prompt e retorna o texto da resposta e o uso de tokens.
+	Generate(ctx context.Context, prompt string) (string, Usage, error)
+
+	// ModelName retorna o nome do modelo usado.
+	ModelName() string
+
+	// Close libera os recursos do provider.
+	Close()
+}
+
+// ImageProvider é um Provider multimodal, que aceita uma imagem junto com o
+// prompt.
+type ImageProvider interface {
+	Provider
+
+	// GenerateWithImage envia o prompt acompanhado da imagem, do tipo mimeType.
+	GenerateWithImage(ctx context.Context, prompt, mimeType string, image []byte) (string, Usage, error)
+}
+
+// Usage é a contagem de tokens de uma chamada ao modelo.
+type Usage struct {
+	PromptTokens    int64
+	CandidateTokens int64
+}
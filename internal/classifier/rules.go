@@ -0,0 +1,352 @@
+package classifier
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuleSpec é uma regra declarada na configuração: se o arquivo atende a
+// todas as condições preenchidas, vai para Folder com o nome de Rename, sem
+// passar pela IA.
+type RuleSpec struct {
+	Name string
+
+	// Condições; campos vazios não restringem
+	NameGlob       string // glob sobre o nome, sem diferenciar maiúsculas (ex: "NF-*.pdf")
+	NameRegex      string // expressão regular sobre o nome; grupos viram variáveis
+	MimeType       string // MIME type exato ou glob (ex: "image/*")
+	MinSize        string // tamanho mínimo (ex: "100KB", "2MB" ou bytes)
+	MaxSize        string // tamanho máximo
+	CreatedAfter   string // data (2006-01-02 ou RFC 3339)
+	CreatedBefore  string
+	ModifiedAfter  string
+	ModifiedBefore string
+	CurrentFolder  string // glob sobre a pasta atual; "/" é a raiz e "**" no fim casa subpastas
+
+	// Ações; Folder e Rename aceitam variáveis como {year} e {base}
+	Folder string
+	Rename string
+}
+
+// Rule é uma RuleSpec validada e compilada.
+type Rule struct {
+	spec           RuleSpec
+	nameRegex      *regexp.Regexp
+	minSize        int64
+	maxSize        int64
+	createdAfter   time.Time
+	createdBefore  time.Time
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+}
+
+// Rules é uma lista de regras avaliadas em ordem; a primeira que casar vence.
+type Rules []*Rule
+
+var templateVar = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// CompileRules valida e compila as regras da configuração.
+func CompileRules(specs []RuleSpec) (Rules, error) {
+	rules := make(Rules, 0, len(specs))
+	for i, spec := range specs {
+		if spec.Name == "" {
+			spec.Name = fmt.Sprintf("regra %d", i+1)
+		}
+		r, err := compileRule(spec)
+		if err != nil {
+			return nil, fmt.Errorf("regra '%s': %w", spec.Name, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func compileRule(spec RuleSpec) (*Rule, error) {
+	spec.Folder = strings.Trim(strings.TrimSpace(spec.Folder), "/")
+	if spec.Folder == "" {
+		return nil, fmt.Errorf("folder é obrigatório")
+	}
+	r := &Rule{spec: spec}
+
+	if spec.NameGlob != "" {
+		if _, err := path.Match(spec.NameGlob, ""); err != nil {
+			return nil, fmt.Errorf("name inválido '%s': %w", spec.NameGlob, err)
+		}
+	}
+	if spec.MimeType != "" {
+		if _, err := path.Match(spec.MimeType, ""); err != nil {
+			return nil, fmt.Errorf("mime_type inválido '%s': %w", spec.MimeType, err)
+		}
+	}
+	if spec.CurrentFolder != "" {
+		if _, err := path.Match(strings.TrimSuffix(spec.CurrentFolder, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("current_folder inválido '%s': %w", spec.CurrentFolder, err)
+		}
+	}
+	if spec.NameRegex != "" {
+		re, err := regexp.Compile(spec.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("name_regex inválido: %w", err)
+		}
+		r.nameRegex = re
+	}
+
+	var err error
+	if r.minSize, err = parseSize(spec.MinSize); err != nil {
+		return nil, fmt.Errorf("min_size: %w", err)
+	}
+	if r.maxSize, err = parseSize(spec.MaxSize); err != nil {
+		return nil, fmt.Errorf("max_size: %w", err)
+	}
+	dates := []struct {
+		value, field string
+		dst          *time.Time
+	}{
+		{spec.CreatedAfter, "created_after", &r.createdAfter},
+		{spec.CreatedBefore, "created_before", &r.createdBefore},
+		{spec.ModifiedAfter, "modified_after", &r.modifiedAfter},
+		{spec.ModifiedBefore, "modified_before", &r.modifiedBefore},
+	}
+	for _, d := range dates {
+		if *d.dst, err = parseRuleDate(d.value); err != nil {
+			return nil, fmt.Errorf("%s: %w", d.field, err)
+		}
+	}
+
+	// Variáveis desconhecidas nos modelos são erro de configuração, não
+	// texto literal no nome da pasta
+	for _, tmpl := range []string{spec.Folder, spec.Rename} {
+		for _, m := range templateVar.FindAllStringSubmatch(tmpl, -1) {
+			if !r.knownVar(m[1]) {
+				return nil, fmt.Errorf("variável desconhecida {%s}", m[1])
+			}
+		}
+	}
+	return r, nil
+}
+
+// Name retorna o nome da regra.
+func (r *Rule) Name() string {
+	return r.spec.Name
+}
+
+// Match avalia as regras em ordem e retorna a sugestão da primeira que casar
+// com o arquivo, ou nil.
+func (rs Rules) Match(file FileMetadata) *Suggestion {
+	for _, r := range rs {
+		if s := r.Match(file); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// Match retorna a sugestão da regra para o arquivo, ou nil se alguma
+// condição não for atendida ou se a pasta de destino ficar vazia.
+func (r *Rule) Match(file FileMetadata) *Suggestion {
+	spec := r.spec
+	if spec.NameGlob != "" {
+		if ok, _ := path.Match(strings.ToLower(spec.NameGlob), strings.ToLower(file.Name)); !ok {
+			return nil
+		}
+	}
+	var groups []string
+	if r.nameRegex != nil {
+		if groups = r.nameRegex.FindStringSubmatch(file.Name); groups == nil {
+			return nil
+		}
+	}
+	if spec.MimeType != "" {
+		if ok, _ := path.Match(spec.MimeType, file.MimeType); !ok {
+			return nil
+		}
+	}
+	if spec.MinSize != "" && file.Size < r.minSize {
+		return nil
+	}
+	if spec.MaxSize != "" && file.Size > r.maxSize {
+		return nil
+	}
+	if !inRange(file.CreatedTime, r.createdAfter, r.createdBefore) ||
+		!inRange(file.ModifiedTime, r.modifiedAfter, r.modifiedBefore) {
+		return nil
+	}
+	if spec.CurrentFolder != "" && !matchFolder(spec.CurrentFolder, file.Folder) {
+		return nil
+	}
+
+	// Um modelo que expande para nada (ex: "{year}" sem data) não indica
+	// pasta alguma; a regra não casa e o arquivo segue para a IA
+	vars := r.vars(file, groups)
+	folder := strings.Trim(expand(spec.Folder, vars), "/")
+	if strings.Trim(folder, "/ ") == "" {
+		return nil
+	}
+	s := &Suggestion{
+		Filename:        file.Name,
+		SuggestedFolder: folder,
+		SuggestedName:   file.Name,
+		Reason:          fmt.Sprintf("Regra '%s'", spec.Name),
+		Confidence:      1,
+		Rule:            spec.Name,
+	}
+	if spec.Rename != "" {
+		if name := strings.TrimSpace(strings.ReplaceAll(expand(spec.Rename, vars), "/", "-")); name != "" {
+			s.SuggestedName = name
+		}
+	}
+	return s
+}
+
+// builtinVars são as variáveis disponíveis em todas as regras.
+var builtinVars = []string{"name", "base", "ext", "year", "month", "day", "folder"}
+
+func (r *Rule) knownVar(name string) bool {
+	for _, v := range builtinVars {
+		if v == name {
+			return true
+		}
+	}
+	if r.nameRegex == nil {
+		return false
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		return n >= 0 && n <= r.nameRegex.NumSubexp()
+	}
+	return r.nameRegex.SubexpIndex(name) >= 0
+}
+
+// vars monta as variáveis dos modelos: nome, extensão, data de criação
+// (ou de modificação, se não houver), pasta atual e os grupos de name_regex.
+func (r *Rule) vars(file FileMetadata, groups []string) map[string]string {
+	ext := path.Ext(file.Name)
+	vars := map[string]string{
+		"name":   file.Name,
+		"base":   strings.TrimSuffix(file.Name, ext),
+		"ext":    ext,
+		"folder": file.Folder,
+	}
+
+	date := file.CreatedTime
+	if date == "" {
+		date = file.ModifiedTime
+	}
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		vars["year"] = t.Format("2006")
+		vars["month"] = t.Format("01")
+		vars["day"] = t.Format("02")
+	}
+
+	if r.nameRegex != nil {
+		for i, g := range groups {
+			vars[strconv.Itoa(i)] = g
+		}
+		for i, name := range r.nameRegex.SubexpNames() {
+			if name != "" && i < len(groups) {
+				if _, builtin := vars[name]; !builtin {
+					vars[name] = groups[i]
+				}
+			}
+		}
+	}
+	return vars
+}
+
+func expand(tmpl string, vars map[string]string) string {
+	return templateVar.ReplaceAllStringFunc(tmpl, func(m string) string {
+		return vars[m[1:len(m)-1]]
+	})
+}
+
+// matchFolder compara a pasta atual (relativa à raiz, "" na raiz) com o
+// padrão de current_folder.
+func matchFolder(pattern, folder string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "/" {
+		return folder == ""
+	}
+	pattern = strings.Trim(pattern, "/")
+
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if ok, _ := path.Match(base, folder); ok {
+			return true
+		}
+		// Qualquer subpasta: basta o prefixo casar
+		parts := strings.Split(folder, "/")
+		for i := range parts {
+			if ok, _ := path.Match(base, strings.Join(parts[:i+1], "/")); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	ok, _ := path.Match(pattern, folder)
+	return ok
+}
+
+// inRange informa se o instante RFC 3339 value está em [after, before).
+// Zeros não restringem; com restrição, datas ilegíveis não casam.
+func inRange(value string, after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && !t.Before(before) {
+		return false
+	}
+	return true
+}
+
+func parseRuleDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida '%s' (use AAAA-MM-DD)", s)
+	}
+	return t, nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	mult   int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// parseSize interpreta tamanhos como "512", "100KB" ou "1.5 GB" (base 1024).
+func parseSize(s string) (int64, error) {
+	orig := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			s, mult = strings.TrimSpace(num), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("tamanho inválido '%s'", orig)
+	}
+	return int64(n * float64(mult)), nil
+}
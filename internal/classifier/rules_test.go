@@ -0,0 +1,160 @@
+package classifier
+
+import "testing"
+
+func compile(t *testing.T, spec RuleSpec) *Rule {
+	t.Helper()
+	rules, err := CompileRules([]RuleSpec{spec})
+	if err != nil {
+		t.Fatalf("CompileRules: %v", err)
+	}
+	return rules[0]
+}
+
+func TestRuleMatch(t *testing.T) {
+	file := FileMetadata{
+		Name:        "NF-1234_acme.pdf",
+		MimeType:    "application/pdf",
+		Size:        200 << 10,
+		CreatedTime: "2024-03-15T10:00:00Z",
+		Folder:      "Entrada/Scanner",
+	}
+
+	tests := []struct {
+		name   string
+		spec   RuleSpec
+		file   FileMetadata
+		folder string // "" = não casa
+		rename string
+	}{
+		{"glob sem diferenciar maiúsculas", RuleSpec{NameGlob: "nf-*.PDF", Folder: "Notas"}, file, "Notas", file.Name},
+		{"glob que não casa", RuleSpec{NameGlob: "*.docx", Folder: "Notas"}, file, "", ""},
+		{"glob de mime type", RuleSpec{MimeType: "application/*", Folder: "Docs"}, file, "Docs", file.Name},
+		{"grupos da regex como variáveis",
+			RuleSpec{NameRegex: `^NF-(?P<numero>\d+)_(\w+)\.pdf$`, Folder: "Notas/{2}/{year}", Rename: "Nota {numero} de {month}-{year}{ext}"},
+			file, "Notas/acme/2024", "Nota 1234 de 03-2024.pdf"},
+		{"regex que não casa", RuleSpec{NameRegex: `^Boleto`, Folder: "Boletos"}, file, "", ""},
+		{"barra no nome vira hífen", RuleSpec{NameRegex: `^NF-(\d+)`, Folder: "Notas", Rename: "{1}/{base}"}, file, "Notas", "1234-NF-1234_acme"},
+		{"tamanho dentro dos limites", RuleSpec{MinSize: "100KB", MaxSize: "1MB", Folder: "Médios"}, file, "Médios", file.Name},
+		{"menor que min_size", RuleSpec{MinSize: "1 MB", Folder: "Grandes"}, file, "", ""},
+		{"maior que max_size", RuleSpec{MaxSize: "100KB", Folder: "Pequenos"}, file, "", ""},
+		{"criado no intervalo", RuleSpec{CreatedAfter: "2024-01-01", CreatedBefore: "2024-04-01", Folder: "T1"}, file, "T1", file.Name},
+		{"limite final exclusivo", RuleSpec{CreatedBefore: "2024-03-15T10:00:00Z", Folder: "Antigos"}, file, "", ""},
+		{"criado antes do intervalo", RuleSpec{CreatedAfter: "2024-06-01", Folder: "Recentes"}, file, "", ""},
+		{"data ilegível não casa com intervalo",
+			RuleSpec{ModifiedAfter: "2024-01-01", Folder: "Recentes"}, file, "", ""},
+		{"pasta atual com /**", RuleSpec{CurrentFolder: "Entrada/**", Folder: "Triagem"}, file, "Triagem", file.Name},
+		{"pasta atual exata", RuleSpec{CurrentFolder: "Entrada", Folder: "Triagem"}, file, "", ""},
+		{"raiz", RuleSpec{CurrentFolder: "/", Folder: "Triagem"}, FileMetadata{Name: "a.txt"}, "Triagem", "a.txt"},
+		{"pasta que expande para nada não casa", RuleSpec{NameGlob: "*.txt", Folder: "{year}/{month}"}, FileMetadata{Name: "a.txt"}, "", ""},
+		{"data de modificação na falta da de criação",
+			RuleSpec{NameGlob: "*.txt", Folder: "Arquivo/{year}"},
+			FileMetadata{Name: "a.txt", ModifiedTime: "2023-07-01T00:00:00Z"}, "Arquivo/2023", "a.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := compile(t, tt.spec).Match(tt.file)
+			if tt.folder == "" {
+				if s != nil {
+					t.Fatalf("Match = %s/%s, want nil", s.SuggestedFolder, s.SuggestedName)
+				}
+				return
+			}
+			if s == nil {
+				t.Fatal("Match = nil")
+			}
+			if s.SuggestedFolder != tt.folder || s.SuggestedName != tt.rename {
+				t.Errorf("Match = %s/%s, want %s/%s", s.SuggestedFolder, s.SuggestedName, tt.folder, tt.rename)
+			}
+		})
+	}
+}
+
+func TestRulesFirstMatchWins(t *testing.T) {
+	rules, err := CompileRules([]RuleSpec{
+		{NameGlob: "*.jpg", Folder: "Fotos"},
+		{MimeType: "image/*", Folder: "Imagens"},
+	})
+	if err != nil {
+		t.Fatalf("CompileRules: %v", err)
+	}
+	if s := rules.Match(FileMetadata{Name: "a.jpg", MimeType: "image/jpeg"}); s == nil || s.Rule != "regra 1" {
+		t.Errorf("Match = %+v, want regra 1", s)
+	}
+	if s := rules.Match(FileMetadata{Name: "a.png", MimeType: "image/png"}); s == nil || s.SuggestedFolder != "Imagens" {
+		t.Errorf("Match = %+v, want Imagens", s)
+	}
+	if s := rules.Match(FileMetadata{Name: "a.txt", MimeType: "text/plain"}); s != nil {
+		t.Errorf("Match = %+v, want nil", s)
+	}
+}
+
+func TestCompileRulesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec RuleSpec
+	}{
+		{"sem pasta", RuleSpec{NameGlob: "*.pdf"}},
+		{"glob inválido", RuleSpec{NameGlob: "[", Folder: "X"}},
+		{"regex inválida", RuleSpec{NameRegex: "(", Folder: "X"}},
+		{"tamanho inválido", RuleSpec{MinSize: "muito", Folder: "X"}},
+		{"data inválida", RuleSpec{CreatedAfter: "15/03/2024", Folder: "X"}},
+		{"variável desconhecida", RuleSpec{Folder: "{cliente}"}},
+		{"grupo inexistente", RuleSpec{NameRegex: `(\d+)`, Folder: "{2}"}},
+	}
+	for _, tt := range tests {
+		if _, err := CompileRules([]RuleSpec{tt.spec}); err == nil {
+			t.Errorf("%s: CompileRules deveria falhar", tt.name)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"100KB", 100 << 10},
+		{"2mb", 2 << 20},
+		{"1.5 GB", 3 << 29},
+		{" 1TB ", 1 << 40},
+	}
+	for _, tt := range tests {
+		if got, err := parseSize(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"KB", "-1", "dez MB"} {
+		if _, err := parseSize(in); err == nil {
+			t.Errorf("parseSize(%q) deveria falhar", in)
+		}
+	}
+}
+
+func TestMatchFolder(t *testing.T) {
+	tests := []struct {
+		pattern, folder string
+		want            bool
+	}{
+		{"/", "", true},
+		{"/", "Docs", false},
+		{"Docs", "Docs", true},
+		{"/Docs/", "Docs", true},
+		{"Docs", "Docs/2024", false},
+		{"Docs/**", "Docs", true},
+		{"Docs/**", "Docs/2024/Jan", true},
+		{"Docs/**", "Documentos", false},
+		{"Clientes/*/**", "Clientes/Acme/NF", true},
+		{"Clientes/*/**", "Clientes", false},
+		{"*/Scanner", "Entrada/Scanner", true},
+	}
+	for _, tt := range tests {
+		if got := matchFolder(tt.pattern, tt.folder); got != tt.want {
+			t.Errorf("matchFolder(%q, %q) = %v, want %v", tt.pattern, tt.folder, got, tt.want)
+		}
+	}
+}
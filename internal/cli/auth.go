@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/vitoramaral10/driver-organizer/internal/config"
 	"github.com/vitoramaral10/driver-organizer/internal/drive"
 )
 
@@ -12,10 +13,19 @@ func newAuthCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "auth",
 		Short: "Autentica com o Google Drive",
-		Long:  "Realiza o fluxo de autenticação OAuth2 com o Google Drive e salva o token localmente.",
+		Long: `Realiza o fluxo de autenticação OAuth2 com o Google Drive e salva o token localmente.
+
+Com --profile, o perfil é criado se ainda não existir e o token fica no diretório dele.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
+			if cfg.Profile != "" && !config.ProfileExists(cfg.Profile) {
+				if err := config.CreateProfile(cfg.Profile, cfg.BackupFolder); err != nil {
+					return err
+				}
+				fmt.Printf("👤 Perfil '%s' criado em %s\n", cfg.Profile, config.ProfileDir(cfg.Profile))
+			}
+
 			fmt.Println("🔐 Iniciando autenticação com Google Drive...")
 			fmt.Printf("   Usando credentials: %s\n", cfg.CredentialsPath)
 			fmt.Printf("   Token será salvo em: %s\n\n", cfg.TokenPath)
@@ -26,7 +36,11 @@ func newAuthCmd() *cobra.Command {
 			}
 
 			fmt.Println("\n✅ Autenticação realizada com sucesso!")
-			fmt.Println("   Agora você pode usar: driver-organizer organize")
+			if cfg.Profile != "" {
+				fmt.Printf("   Agora você pode usar: driver-organizer organize --profile %s\n", cfg.Profile)
+			} else {
+				fmt.Println("   Agora você pode usar: driver-organizer organize")
+			}
 			return nil
 		},
 	}
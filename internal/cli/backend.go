@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/ratelimit"
+)
+
+// openBackend abre o backend usado pelos comandos. É uma variável para que os
+// testes troquem o backend por um MemoryBackend.
+var openBackend = openConfiguredBackend
+
+// openConfiguredBackend abre o backend de armazenamento escolhido em
+// cfg.Backend. As mensagens de conexão vão para out.
+func openConfiguredBackend(ctx context.Context, out io.Writer) (drive.Backend, error) {
+	switch cfg.Backend {
+	case "", "drive":
+		fmt.Fprintln(out, "📁 Conectando ao Google Drive...")
+		client, err := drive.NewClient(ctx, cfg.CredentialsPath, cfg.TokenPath)
+		if err != nil {
+			return nil, err
+		}
+		b, err := drive.NewGoogleBackendFromClient(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		b.SetRateLimiter(ratelimit.New("drive", float64(cfg.RateLimit), cfg.RateLimit))
+
+		if cfg.SharedDrive != "" {
+			d, err := b.UseSharedDrive(ctx, cfg.SharedDrive)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(out, "👥 Drive compartilhado: %s\n", d.Name)
+		}
+		return b, nil
+
+	case "local":
+		if cfg.SharedDrive != "" {
+			return nil, fmt.Errorf("--shared-drive só pode ser usado com --backend drive")
+		}
+		if cfg.LocalRoot == "" {
+			return nil, fmt.Errorf("informe o diretório com --root ao usar --backend local")
+		}
+		fmt.Fprintf(out, "📁 Usando diretório local: %s\n", cfg.LocalRoot)
+		return drive.NewLocalBackend(cfg.LocalRoot)
+
+	default:
+		return nil, fmt.Errorf("backend desconhecido '%s' (use drive ou local)", cfg.Backend)
+	}
+}
+
+// backendLabel identifica o backend no diário de operações, para que o undo
+// reabra o mesmo Drive, drive compartilhado ou diretório.
+func backendLabel(b drive.Backend) string {
+	switch b := b.(type) {
+	case *drive.LocalBackend:
+		return "local:" + b.Root()
+	case *drive.GoogleBackend:
+		if id := b.SharedDriveID(); id != "" {
+			return "drive:" + id
+		}
+	}
+	return "drive"
+}
+
+// openBackendByLabel abre o backend identificado por backendLabel.
+func openBackendByLabel(ctx context.Context, label string, out io.Writer) (drive.Backend, error) {
+	cfg.SharedDrive = ""
+	if root, ok := strings.CutPrefix(label, "local:"); ok {
+		cfg.Backend = "local"
+		cfg.LocalRoot = root
+	} else if id, ok := strings.CutPrefix(label, "drive:"); ok {
+		cfg.Backend = "drive"
+		cfg.SharedDrive = id
+	} else {
+		cfg.Backend = label
+	}
+	return openBackend(ctx, out)
+}
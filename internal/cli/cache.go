@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Gerencia o cache de classificações",
+		Long: `O cache guarda as sugestões da IA por checksum do conteúdo, para que
+execuções seguintes não paguem de novo pelos mesmos arquivos. Entradas de outro
+modelo ou de outra versão do prompt são ignoradas automaticamente.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "Mostra estatísticas do cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			model := currentModelName()
+			cache, err := classifier.OpenCache(config.CachePath(), model)
+			if err != nil {
+				return err
+			}
+			defer cache.Close()
+
+			stats := cache.Stats()
+			fmt.Printf("🗃️  Cache de classificações: %s\n", stats.Path)
+			fmt.Printf("   Tamanho em disco: %s\n", formatSize(stats.SizeBytes))
+			fmt.Printf("   Entradas: %d\n", stats.Total)
+			fmt.Printf("   Válidas para %s (prompt v%d): %d\n", model, classifier.PromptVersion, stats.Valid)
+			fmt.Printf("   De outros modelos: %d\n", stats.Stale)
+
+			if len(stats.ByModel) > 0 {
+				models := make([]string, 0, len(stats.ByModel))
+				for m := range stats.ByModel {
+					models = append(models, m)
+				}
+				sort.Strings(models)
+
+				fmt.Println("\n   Por modelo:")
+				for _, m := range models {
+					fmt.Printf("   - %s: %d\n", m, stats.ByModel[m])
+				}
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Apaga o cache de classificações",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := classifier.ClearCache(config.CachePath()); err != nil {
+				return err
+			}
+			fmt.Println("✅ Cache de classificações apagado.")
+			return nil
+		},
+	})
+
+	return cmd
+}
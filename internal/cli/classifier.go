@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/ratelimit"
+)
+
+// newClassifierFlags cria as flags do classificador, compartilhadas pelos
+// comandos que classificam arquivos (organize e watch). Como o viper guarda
+// um único flag por chave, o mesmo conjunto é adicionado a cada comando.
+func newClassifierFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("classifier", pflag.ContinueOnError)
+
+	fs.String("gemini-api-key", "", "API key do Google AI Studio para Gemini")
+	fs.String("gemini-model", "gemini-2.0-flash", "modelo Gemini a usar")
+	fs.Int("batch-size", 20, "arquivos por lote de classificação")
+	fs.Float64("max-cost", 5.0, "custo máximo estimado em USD")
+	fs.Int("ai-rate-limit", 15, "requisições por minuto ao provedor de IA (0 desativa)")
+	fs.Float64("min-confidence", 0.85, "confiança mínima (0 a 1) para aplicar automaticamente com --auto")
+	fs.String("provider", "gemini", "provedor de IA: gemini ou openai (qualquer endpoint compatível, ex: Ollama)")
+	fs.String("openai-base-url", "http://localhost:11434/v1", "URL da API compatível com OpenAI")
+	fs.String("openai-model", "llama3.1", "modelo a usar no endpoint compatível com OpenAI")
+	fs.String("classifier", "ai", "classificador: ai (provedor de IA) ou offline (heurística local)")
+	fs.Bool("offline-fallback", true, "usa a heurística local quando a IA falhar")
+	fs.Bool("images", false, "envia a miniatura de fotos e imagens à IA quando o nome não basta (só Gemini)")
+	fs.Int("max-image-calls", 20, "máximo de chamadas à IA com imagem por sessão (0 = sem limite)")
+
+	viper.BindPFlag("gemini_api_key", fs.Lookup("gemini-api-key"))
+	viper.BindPFlag("gemini_model", fs.Lookup("gemini-model"))
+	viper.BindPFlag("batch_size", fs.Lookup("batch-size"))
+	viper.BindPFlag("max_cost", fs.Lookup("max-cost"))
+	viper.BindPFlag("ai_rate_limit", fs.Lookup("ai-rate-limit"))
+	viper.BindPFlag("ai_provider", fs.Lookup("provider"))
+	viper.BindPFlag("openai_base_url", fs.Lookup("openai-base-url"))
+	viper.BindPFlag("openai_model", fs.Lookup("openai-model"))
+	viper.BindPFlag("classifier", fs.Lookup("classifier"))
+	viper.BindPFlag("offline_fallback", fs.Lookup("offline-fallback"))
+	viper.BindPFlag("min_confidence", fs.Lookup("min-confidence"))
+	viper.BindPFlag("image_classification", fs.Lookup("images"))
+	viper.BindPFlag("max_image_calls", fs.Lookup("max-image-calls"))
+
+	return fs
+}
+
+// prepareProvider valida o provedor de IA configurado e garante as credenciais
+// necessárias antes de qualquer arquivo ser movido.
+func prepareProvider() error {
+	switch cfg.Classifier {
+	case "", "ai":
+	case "offline":
+		return nil
+	default:
+		return fmt.Errorf("classificador desconhecido '%s' (use ai ou offline)", cfg.Classifier)
+	}
+
+	switch cfg.AIProvider {
+	case "", "gemini":
+		return ensureGeminiAPIKey()
+	case "openai":
+		return nil
+	default:
+		return fmt.Errorf("provedor de IA desconhecido '%s' (use gemini ou openai)", cfg.AIProvider)
+	}
+}
+
+// openClassifier cria o classificador configurado, com as regras da
+// configuração avaliadas antes dele.
+func openClassifier(ctx context.Context) (*classifier.Classifier, error) {
+	rules, err := compileRules()
+	if err != nil {
+		return nil, err
+	}
+
+	cls, err := newClassifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		cls.SetRules(rules)
+		fmt.Printf("   📏 %d regras da configuração avaliadas antes da IA\n", len(rules))
+	}
+	return cls, nil
+}
+
+// newClassifier cria o classificador: a heurística local quando
+// cfg.Classifier é "offline", ou o provedor de cfg.AIProvider com fallback
+// opcional para a heurística.
+func newClassifier(ctx context.Context) (*classifier.Classifier, error) {
+	if cfg.Classifier == "offline" {
+		fmt.Println("   Modo offline: classificação por heurística local (sem IA)")
+		return classifier.NewOffline(), nil
+	}
+
+	var cls *classifier.Classifier
+	switch cfg.AIProvider {
+	case "", "gemini":
+		var err error
+		cls, err = classifier.NewClassifier(ctx, cfg.GeminiAPIKey, cfg.GeminiModel)
+		if err != nil {
+			return nil, err
+		}
+
+	case "openai":
+		provider, err := classifier.NewOpenAIProvider(cfg.OpenAIBaseURL, cfg.OpenAIAPIKey, cfg.OpenAIModel)
+		if err != nil {
+			return nil, err
+		}
+		cls = classifier.New(provider)
+
+	default:
+		return nil, fmt.Errorf("provedor de IA desconhecido '%s' (use gemini ou openai)", cfg.AIProvider)
+	}
+
+	if cfg.OfflineFallback {
+		cls.EnableFallback()
+	}
+	cls.SetCostTracker(classifier.NewCostTracker(modelPrices(), cfg.MaxCost))
+	cls.SetRateLimiter(ratelimit.PerMinute("ai", float64(cfg.AIRateLimit), 1))
+	cls.SetImageLimit(cfg.MaxImageCalls)
+	if cfg.ImageClassify && !cls.SupportsImages() {
+		slog.Warn("image_classification ignorado: o provedor de IA não aceita imagens", "provider", cfg.AIProvider)
+	}
+	return cls, nil
+}
+
+// compileRules valida e compila a seção "rules" da configuração.
+func compileRules() (classifier.Rules, error) {
+	specs := make([]classifier.RuleSpec, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		specs[i] = classifier.RuleSpec{
+			Name:           r.Name,
+			NameGlob:       r.Match.Name,
+			NameRegex:      r.Match.NameRegex,
+			MimeType:       r.Match.MimeType,
+			MinSize:        r.Match.MinSize,
+			MaxSize:        r.Match.MaxSize,
+			CreatedAfter:   r.Match.CreatedAfter,
+			CreatedBefore:  r.Match.CreatedBefore,
+			ModifiedAfter:  r.Match.ModifiedAfter,
+			ModifiedBefore: r.Match.ModifiedBefore,
+			CurrentFolder:  r.Match.Folder,
+			Folder:         r.Folder,
+			Rename:         r.Rename,
+		}
+	}
+
+	rules, err := classifier.CompileRules(specs)
+	if err != nil {
+		return nil, fmt.Errorf("erro nas regras da configuração: %w", err)
+	}
+	return rules, nil
+}
+
+// currentModelName retorna o nome do modelo que openClassifier usaria, sem
+// criar o classificador. Usado para conferir a validade do cache.
+func currentModelName() string {
+	if cfg.Classifier == "offline" {
+		return "offline"
+	}
+	if cfg.AIProvider == "openai" {
+		return cfg.OpenAIModel
+	}
+	return cfg.GeminiModel
+}
+
+// modelPrices combina a tabela de preços padrão com a seção "pricing" da
+// configuração, que tem precedência.
+func modelPrices() map[string]classifier.ModelPrice {
+	prices := make(map[string]classifier.ModelPrice, len(classifier.DefaultPrices)+len(cfg.Pricing))
+	for model, p := range classifier.DefaultPrices {
+		prices[model] = p
+	}
+	for model, p := range cfg.Pricing {
+		prices[strings.ToLower(model)] = classifier.ModelPrice{Input: p.Input, Output: p.Output}
+	}
+	return prices
+}
+
+// formatCost formata o custo acumulado e o orçamento, ex: "$0.0123 de $5.00".
+func formatCost(t *classifier.CostTracker) string {
+	if t.Limit() <= 0 {
+		return fmt.Sprintf("$%.4f (sem limite)", t.Total())
+	}
+	return fmt.Sprintf("$%.4f de $%.2f", t.Total(), t.Limit())
+}
+
+// confirmBudgetIncrease pergunta se o orçamento deve ser aumentado em mais
+// max_cost. Retorna true se o usuário aceitou.
+func confirmBudgetIncrease(reader *bufio.Reader, t *classifier.CostTracker) bool {
+	fmt.Printf("   Aumentar o orçamento em $%.2f e continuar? [s/N]: ", cfg.MaxCost)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "s" && answer != "sim" {
+		return false
+	}
+	t.SetLimit(t.Limit() + cfg.MaxCost)
+	fmt.Printf("   💰 Novo orçamento: $%.2f\n", t.Limit())
+	return true
+}
+
+// printCostSummary mostra o custo da sessão por modelo.
+func printCostSummary(t *classifier.CostTracker) {
+	if t == nil {
+		return
+	}
+	usage := t.Breakdown()
+	if len(usage) == 0 {
+		return
+	}
+
+	fmt.Println("\n💰 Custo da IA:")
+	for _, u := range usage {
+		fmt.Printf("   %s: %d chamadas, %d tokens de entrada, %d de saída → $%.4f\n",
+			u.Model, u.Requests, u.PromptTokens, u.CandidateTokens, u.Cost)
+	}
+	fmt.Printf("   Total: %s\n", formatCost(t))
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/extract"
+)
+
+// textPrefixBytes é quanto baixar de arquivos de texto: o prompt usa apenas o
+// começo do conteúdo.
+const textPrefixBytes = 64 << 10
+
+// imageMaxDim é o lado maior, em pixels, das imagens enviadas à IA quando o
+// arquivo original precisa ser reduzido.
+const imageMaxDim = 512
+
+var (
+	errContentBudget   = errors.New("orçamento de download (content_budget) esgotado")
+	errContentTooLarge = errors.New("arquivo maior que content_max_bytes")
+)
+
+// contentFetcher baixa o texto dos arquivos para a classificação por conteúdo,
+// respeitando o limite por arquivo (content_max_bytes) e o orçamento de bytes
+// da sessão (content_budget).
+type contentFetcher struct {
+	backend  drive.Backend
+	maxBytes int64
+	budget   int64
+	used     int64
+}
+
+func newContentFetcher(b drive.Backend) *contentFetcher {
+	return &contentFetcher{
+		backend:  b,
+		maxBytes: cfg.ContentMaxBytes,
+		budget:   cfg.ContentBudget,
+	}
+}
+
+// Text baixa f e extrai o texto das primeiras content_max_pages páginas.
+func (c *contentFetcher) Text(ctx context.Context, f *drive.FileInfo) (string, error) {
+	mimeType := f.MimeType
+	exported := drive.IsExportable(mimeType)
+	if !exported && !extract.Supported(mimeType) {
+		return "", extract.ErrUnsupported
+	}
+
+	// Texto puro e documentos exportados só precisam do começo; os demais
+	// formatos precisam do arquivo inteiro
+	limit := c.maxBytes
+	if exported || extract.IsPlainText(mimeType) {
+		limit = min(limit, textPrefixBytes)
+	} else if f.Size > c.maxBytes {
+		return "", fmt.Errorf("%w (%s)", errContentTooLarge, formatSize(f.Size))
+	}
+
+	expected := limit
+	if f.Size > 0 {
+		expected = min(f.Size, limit)
+	}
+	if c.budget > 0 && c.used+expected > c.budget {
+		return "", errContentBudget
+	}
+
+	data, err := c.backend.Download(ctx, f.ID, mimeType, limit)
+	c.used += int64(len(data))
+	if err != nil {
+		return "", err
+	}
+	slog.Debug("conteúdo obtido", "file", f.Name, "bytes", len(data), "used", c.used, "budget", c.budget)
+
+	if exported {
+		mimeType = "text/plain"
+	}
+	return extract.Text(mimeType, data, cfg.ContentMaxPages)
+}
+
+// Image retorna a miniatura do Drive para f ou, sem ela, uma cópia reduzida do
+// próprio arquivo, junto com o tipo MIME da imagem.
+func (c *contentFetcher) Image(ctx context.Context, f *drive.FileInfo) ([]byte, string, error) {
+	data, mimeType, err := c.backend.Thumbnail(ctx, f.ID, c.maxBytes)
+	c.used += int64(len(data))
+	if err == nil && len(data) > 0 {
+		return data, mimeType, nil
+	}
+	if err != nil && !errors.Is(err, drive.ErrNoThumbnail) {
+		slog.Debug("miniatura indisponível, usando o arquivo", "file", f.Name, "error", err)
+	}
+
+	if f.Size > c.maxBytes {
+		return nil, "", fmt.Errorf("%w (%s)", errContentTooLarge, formatSize(f.Size))
+	}
+	if c.budget > 0 && c.used+f.Size > c.budget {
+		return nil, "", errContentBudget
+	}
+
+	data, err = c.backend.Download(ctx, f.ID, f.MimeType, c.maxBytes)
+	c.used += int64(len(data))
+	if err != nil {
+		return nil, "", err
+	}
+	slog.Debug("imagem obtida", "file", f.Name, "bytes", len(data), "used", c.used, "budget", c.budget)
+
+	data, err = extract.Downscale(data, imageMaxDim)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "image/jpeg", nil
+}
+
+// classifyWithContent baixa o texto de f e pede uma nova classificação com ele.
+// Com image_classification, imagens vão para a IA como imagem.
+func classifyWithContent(ctx context.Context, fetcher *contentFetcher, cls *classifier.Classifier, cache *classifier.Cache, f *drive.FileInfo, existingFolders []string) (*classifier.Suggestion, error) {
+	if cfg.ImageClassify && strings.HasPrefix(f.MimeType, "image/") && cls.SupportsImages() {
+		return classifyWithImage(ctx, fetcher, cls, cache, f, existingFolders)
+	}
+
+	text, err := fetcher.Text(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return cls.ClassifyWithContent(ctx, fileMetadata(f), text, existingFolders)
+}
+
+// classifyWithImage classifica f enviando a imagem à IA. O resultado fica no
+// cache pelo checksum, para que cópias da mesma imagem não gerem novas chamadas.
+func classifyWithImage(ctx context.Context, fetcher *contentFetcher, cls *classifier.Classifier, cache *classifier.Cache, f *drive.FileInfo, existingFolders []string) (*classifier.Suggestion, error) {
+	key := classifier.ImageCacheKey(f.Md5Checksum)
+	if key != "" {
+		if s := cache.Get(key, f.Name); s != nil {
+			slog.Debug("classificação por imagem em cache", "file", f.Name)
+			return s, nil
+		}
+	}
+
+	image := func(ctx context.Context) ([]byte, string, error) {
+		return fetcher.Image(ctx, f)
+	}
+	s, err := cls.ClassifyImage(ctx, fileMetadata(f), image, existingFolders)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		cache.Set(key, s)
+	}
+	return s, nil
+}
+
+// reclassifyWithContent tenta classificar f pelo conteúdo e mostra a nova
+// sugestão, que vai para o cache. Se não for possível, avisa e mantém s.
+func reclassifyWithContent(ctx context.Context, sess *reviewSession, f *drive.FileInfo, s *classifier.Suggestion) *classifier.Suggestion {
+	fmt.Println("   🔎 Analisando o conteúdo do arquivo...")
+
+	newSuggestion, err := sess.Content(ctx, f)
+	if errors.Is(err, extract.ErrScanned) {
+		fmt.Println("   📷 PDF digitalizado, sem camada de texto: o conteúdo não pode ser lido")
+		return s
+	}
+	if err != nil {
+		fmt.Printf("   ⚠️  Não foi possível usar o conteúdo: %v\n", err)
+		return s
+	}
+
+	printSuggestion("Sugestão pelo conteúdo", f, newSuggestion)
+	return newSuggestion
+}
+
+// printSuggestion mostra uma sugestão com o título informado.
+func printSuggestion(title string, f *drive.FileInfo, s *classifier.Suggestion) {
+	fmt.Printf("\n   🤖 %s:\n", title)
+	fmt.Printf("      Pasta: %s\n", s.SuggestedFolder)
+	if s.SuggestedName != "" && s.SuggestedName != f.Name {
+		fmt.Printf("      Nome: %s → %s\n", f.Name, s.SuggestedName)
+	}
+	fmt.Printf("      Motivo: %s\n", s.Reason)
+	fmt.Printf("      Confiança: %.0f%%\n", s.Confidence*100)
+}
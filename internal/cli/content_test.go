@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/extract"
+)
+
+func TestContentFetcherText(t *testing.T) {
+	orig := cfg
+	t.Cleanup(func() { cfg = orig })
+	cfg = config.DefaultConfig()
+
+	m := drive.NewMemoryBackend()
+	file := func(name, mimeType, content string) *drive.FileInfo {
+		f := m.AddFile(name, mimeType, int64(len(content)))
+		m.SetContent(f.ID, []byte(content))
+		return f
+	}
+	notes := file("notas.txt", "text/plain", "  Reunião de condomínio\n")
+	doc := file("Ata", "application/vnd.google-apps.document", "Ata da assembleia")
+	photo := file("foto.jpg", "image/jpeg", "jpeg")
+	large := file("manual.pdf", "application/pdf", "%PDF-1.4 muito grande")
+	other := file("outra.txt", "text/plain", "0123456789")
+
+	c := &contentFetcher{backend: m, maxBytes: 16, budget: 40}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		file *drive.FileInfo
+		want string
+		err  error
+	}{
+		{"texto puro", notes, "Reunião de co", nil},
+		{"documento do Google exportado", doc, "Ata da assemblei", nil},
+		{"imagem", photo, "", extract.ErrUnsupported},
+		{"maior que content_max_bytes", large, "", errContentTooLarge},
+		{"orçamento esgotado", other, "", errContentBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Text(ctx, tt.file)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Errorf("Text = %q, %v; want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+	if c.used != 32 {
+		t.Errorf("bytes baixados = %d, want 32", c.used)
+	}
+}
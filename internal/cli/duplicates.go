@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/dedup"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func newDuplicatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "duplicates",
+		Short: "Encontra arquivos duplicados pelo checksum MD5",
+		Long: `Percorre o Drive inteiro (ou a pasta de --folder) e agrupa os arquivos com o
+mesmo conteúdo (checksum MD5 e tamanho), mostrando o caminho de cada cópia e o
+espaço desperdiçado. Documentos do Google não têm checksum e são ignorados.
+
+Para resolver, use --interactive para escolher a cópia a manter em cada grupo,
+ou --keep com uma regra:
+  newest     mantém a cópia modificada por último
+  organized  mantém a cópia em uma pasta organizada (fora da raiz e do backup)
+
+As demais cópias vão para a lixeira, de onde podem ser restauradas.`,
+		Args: cobra.NoArgs,
+		RunE: runDuplicates,
+	}
+
+	cmd.Flags().String("folder", "", "caminho da pasta a verificar (padrão: o Drive inteiro)")
+	cmd.Flags().String("keep", "", "resolve todos os grupos pela regra: newest ou organized")
+	cmd.Flags().BoolP("interactive", "i", false, "escolhe a cópia a manter em cada grupo")
+	cmd.Flags().BoolP("yes", "y", false, "não pede confirmação com --keep")
+
+	return cmd
+}
+
+func runDuplicates(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	folder, _ := cmd.Flags().GetString("folder")
+	keep, _ := cmd.Flags().GetString("keep")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if keep != "" && interactive {
+		return fmt.Errorf("use --keep ou --interactive, não os dois")
+	}
+	if keep != "" && keep != dedup.KeepNewest && keep != dedup.KeepOrganized {
+		return fmt.Errorf("regra desconhecida '%s' (use %s ou %s)", keep, dedup.KeepNewest, dedup.KeepOrganized)
+	}
+	if interactive && !isTerminal(os.Stdin) {
+		return fmt.Errorf("a entrada não é um terminal; use --keep para resolver sem interação")
+	}
+
+	backend, err := openBackend(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	folderID := backend.RootID()
+	if folder != "" {
+		f, err := drive.FindNestedFolder(ctx, backend, folder, backend.RootID())
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return fmt.Errorf("pasta '%s' não encontrada", folder)
+		}
+		folderID = f.ID
+		fmt.Printf("📋 Listando arquivos em '%s'...\n", folder)
+	} else {
+		fmt.Println("📋 Listando todos os arquivos...")
+	}
+
+	var items []dedup.Item
+	err = drive.WalkFiles(ctx, backend, folderID, strings.Trim(folder, "/"), func(f *drive.FileInfo, path string) {
+		items = append(items, dedup.Item{File: f, Path: path})
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao listar arquivos: %w", err)
+	}
+	fmt.Printf("   Encontrados: %d arquivos\n\n", len(items))
+
+	groups, err := dedup.Find(ctx, backend, items)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Println("✅ Nenhum arquivo duplicado encontrado.")
+		return nil
+	}
+
+	unorganized := []string{strings.Split(cfg.BackupFolder, "/")[0]}
+
+	if !interactive {
+		for i, g := range groups {
+			printDuplicateGroup(i, len(groups), g, unorganized)
+		}
+	}
+	fmt.Printf("\n📊 %d grupos de duplicados, %s desperdiçados\n", len(groups), formatSize(dedup.TotalWasted(groups)))
+
+	if keep == "" && !interactive {
+		fmt.Println("\n💡 Use --keep newest, --keep organized ou --interactive para remover as cópias.")
+		return nil
+	}
+
+	dryRun := cfg.DryRun
+	if dryRun {
+		fmt.Println("🔍 MODO DRY-RUN: nenhum arquivo será removido")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	if keep != "" && !dryRun && !yes {
+		fmt.Printf("\nMover para a lixeira as cópias, mantendo uma por grupo (regra: %s)? [s/N]: ", keep)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "s" && answer != "sim" {
+			fmt.Println("❌ Cancelado.")
+			return nil
+		}
+	}
+	fmt.Println()
+
+	trashed, skipped := 0, 0
+	var freed int64
+	var failed []string
+	for i, g := range groups {
+		if ctx.Err() != nil {
+			fmt.Println("\n⚠️  Operação cancelada.")
+			break
+		}
+
+		var kept int
+		if interactive {
+			printDuplicateGroup(i, len(groups), g, unorganized)
+			var quit bool
+			kept, quit = askDuplicateKeep(reader, g, unorganized)
+			if quit {
+				break
+			}
+			if kept < 0 {
+				skipped++
+				continue
+			}
+		} else {
+			kept, _ = dedup.Keep(g, keep, unorganized)
+		}
+
+		fmt.Printf("   ✅ Mantendo: %s\n", g.Items[kept].Path)
+		for j, it := range g.Items {
+			if j == kept {
+				continue
+			}
+			if dryRun {
+				fmt.Printf("   [DRY-RUN] Moveria para a lixeira: %s\n", it.Path)
+				trashed++
+				freed += g.Size
+				continue
+			}
+			if err := backend.TrashFile(ctx, it.File.ID); err != nil {
+				fmt.Printf("   ❌ Erro ao mover '%s' para a lixeira: %v\n", it.Path, err)
+				failed = append(failed, it.Path)
+				continue
+			}
+			fmt.Printf("   🗑️  %s\n", it.Path)
+			trashed++
+			freed += g.Size
+		}
+	}
+
+	fmt.Printf("\n✅ Duplicados resolvidos!\n")
+	fmt.Printf("   🗑️  Na lixeira: %d (%s liberados)\n", trashed, formatSize(freed))
+	if skipped > 0 {
+		fmt.Printf("   ⏭️  Grupos pulados: %d\n", skipped)
+	}
+	if len(failed) > 0 {
+		fmt.Printf("   ❌ Erros: %d\n", len(failed))
+		return fmt.Errorf("%d arquivos não puderam ir para a lixeira", len(failed))
+	}
+	return nil
+}
+
+// printDuplicateGroup mostra as cópias de um grupo, marcando as que estão em
+// pastas organizadas.
+func printDuplicateGroup(i, total int, g dedup.Group, unorganized []string) {
+	fmt.Printf("\n📑 [%d/%d] %d cópias de %s (desperdício: %s)\n",
+		i+1, total, len(g.Items), formatSize(g.Size), formatSize(g.Wasted()))
+	for j, it := range g.Items {
+		mark := ""
+		if dedup.IsOrganized(it.Path, unorganized) {
+			mark = "  📁 organizada"
+		}
+		fmt.Printf("   %d. %s  (modificado em %s)%s\n", j+1, it.Path, formatModified(it.File.ModifiedTime), mark)
+	}
+}
+
+// askDuplicateKeep pergunta qual cópia manter. Retorna o índice escolhido,
+// -1 para pular o grupo, e quit quando o usuário quer sair.
+func askDuplicateKeep(reader *bufio.Reader, g dedup.Group, unorganized []string) (kept int, quit bool) {
+	for {
+		fmt.Printf("   Manter qual? [1-%d] / (r)ecente / (o)rganizada / (p)ular / (q)uit: ", len(g.Items))
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return -1, true
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		switch answer {
+		case "r":
+			k, _ := dedup.Keep(g, dedup.KeepNewest, unorganized)
+			return k, false
+		case "o":
+			k, _ := dedup.Keep(g, dedup.KeepOrganized, unorganized)
+			return k, false
+		case "p":
+			return -1, false
+		case "q":
+			return -1, true
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(g.Items) {
+			return n - 1, false
+		}
+		fmt.Println("   ⚠️  Opção inválida")
+	}
+}
+
+// formatModified formata uma data RFC 3339 da API no horário local.
+func formatModified(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("02/01/2006 15:04")
+}
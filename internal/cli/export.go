@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/export"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exporta o inventário de arquivos para CSV, JSON Lines ou SQLite",
+		Long: `Percorre o Drive inteiro (ou a pasta de --folder) e grava uma linha por
+arquivo com caminho completo, MIME type, tamanho, datas, donos, checksum MD5,
+link de visualização e se o arquivo está compartilhado. Os arquivos são gravados
+à medida que são listados, sem manter o inventário em memória.
+
+O formato é deduzido da extensão de --output (.csv, .jsonl, .db/.sqlite) ou
+informado com --format. Em CSV e JSON Lines, --output - grava na saída padrão.`,
+		Args: cobra.NoArgs,
+		RunE: runExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "arquivo de saída (obrigatório)")
+	cmd.Flags().String("format", "", "formato: csv, jsonl ou sqlite (padrão: pela extensão)")
+	cmd.Flags().String("folder", "", "caminho da pasta a exportar (padrão: o Drive inteiro)")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	folder, _ := cmd.Flags().GetString("folder")
+
+	if format == "" {
+		if format = export.FormatFromPath(output); format == "" {
+			return fmt.Errorf("não foi possível deduzir o formato de '%s'; use --format csv, jsonl ou sqlite", output)
+		}
+	}
+	if format != export.FormatCSV && format != export.FormatJSONL && format != export.FormatSQLite {
+		return fmt.Errorf("formato desconhecido '%s' (use csv, jsonl ou sqlite)", format)
+	}
+
+	// Com a saída padrão ocupada pelo inventário, o progresso vai para o stderr
+	progress := cmd.OutOrStdout()
+	if output == "-" {
+		if format == export.FormatSQLite {
+			return fmt.Errorf("o formato sqlite precisa de um arquivo de saída")
+		}
+		progress = cmd.ErrOrStderr()
+	}
+
+	backend, err := openBackend(ctx, progress)
+	if err != nil {
+		return err
+	}
+
+	folder = strings.Trim(folder, "/")
+	folderID := backend.RootID()
+	if folder != "" {
+		f, err := drive.FindNestedFolder(ctx, backend, folder, backend.RootID())
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return fmt.Errorf("pasta '%s' não encontrada", folder)
+		}
+		folderID = f.ID
+	}
+
+	var w export.Writer
+	if output == "-" {
+		w, err = export.New(cmd.OutOrStdout(), format)
+	} else {
+		w, err = export.Create(output, format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if folder != "" {
+		fmt.Fprintf(progress, "📋 Exportando arquivos em '%s'...\n", folder)
+	} else {
+		fmt.Fprintln(progress, "📋 Exportando todos os arquivos...")
+	}
+
+	count, err := exportFiles(ctx, backend, folderID, folder, w, progress)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progress, "✅ %d arquivos exportados para %s (%s)\n", count, output, format)
+	return nil
+}
+
+// exportFiles grava cada arquivo encontrado sob folderID assim que ele é
+// listado, informando o andamento em progress. Um erro de gravação interrompe a
+// listagem.
+func exportFiles(ctx context.Context, backend drive.Backend, folderID, prefix string, w export.Writer, progress io.Writer) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	count := 0
+	var writeErr error
+	err := drive.WalkFiles(ctx, backend, folderID, prefix, func(f *drive.FileInfo, p string) {
+		if writeErr != nil {
+			return
+		}
+
+		// Backends sem checksum na listagem (ex: local) o calculam sob demanda;
+		// documentos do Google não têm checksum
+		if f.Md5Checksum == "" && !strings.HasPrefix(f.MimeType, "application/vnd.google-apps.") {
+			sum, err := backend.Checksum(ctx, f.ID)
+			if err != nil {
+				slog.Warn("erro ao calcular checksum, exportando sem ele", "file", p, "error", err)
+			}
+			f.Md5Checksum = sum
+		}
+
+		if writeErr = w.Write(export.NewRecord(f, p)); writeErr != nil {
+			cancel()
+			return
+		}
+		count++
+		if count%1000 == 0 {
+			fmt.Fprintf(progress, "   %d arquivos...\n", count)
+		}
+	})
+	if writeErr != nil {
+		return count, writeErr
+	}
+	if err != nil {
+		return count, fmt.Errorf("erro ao listar arquivos: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/export"
+)
+
+// runOutput executa o comando e retorna a saída padrão e a de erros.
+func runOutput(t *testing.T, args ...string) (stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	return out.String(), errOut.String()
+}
+
+func TestExportToStdoutIsOnlyData(t *testing.T) {
+	m, _, _, _, _ := sampleDrive()
+	useMemoryBackend(t, m)
+
+	stdout, stderr := runOutput(t, "export", "-o", "-", "--format", "jsonl")
+
+	var paths []string
+	for line := range strings.Lines(stdout) {
+		var r export.Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("linha fora do formato JSON Lines: %q (%v)", line, err)
+		}
+		paths = append(paths, r.Path)
+	}
+	slices.Sort(paths)
+	want := []string{"Antigo/rascunho.txt", "boleto_luz.pdf", "notas.txt", "screenshot 2024-01-10.png"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("caminhos = %v, want %v", paths, want)
+	}
+	if !strings.Contains(stderr, "Usando backend em memória") || !strings.Contains(stderr, "4 arquivos exportados") {
+		t.Errorf("progresso não foi para o stderr: %q", stderr)
+	}
+}
+
+func TestExportLocalCSVToStdout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", "Docs/b.txt"} {
+		p := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, stderr := runOutput(t, "export", "--backend", "local", "--root", root, "-o", "-", "--format", "csv")
+
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV inválido: %v\n%s", err, stdout)
+	}
+	if len(rows) != 3 || rows[0][0] != "id" {
+		t.Fatalf("CSV = %v, want cabeçalho e 2 arquivos", rows)
+	}
+	if !strings.Contains(stderr, "Usando diretório local") {
+		t.Errorf("mensagem do backend não foi para o stderr: %q", stderr)
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// fuzzyScore informa se as letras de query aparecem em s, na ordem, sem
+// diferenciar maiúsculas. A pontuação favorece letras consecutivas, inícios
+// de palavra e nomes curtos. Uma query vazia casa com tudo.
+func fuzzyScore(query, s string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	r := []rune(strings.ToLower(s))
+
+	score, qi, prev := 0, 0, -2
+	for i, c := range r {
+		if qi == len(q) {
+			break
+		}
+		if c != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune(" /-_.", r[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score - len(r)/10, true
+}
+
+// fuzzyFilter retorna os itens que casam com query, do mais ao menos
+// relevante.
+func fuzzyFilter(query string, items []string) []string {
+	type match struct {
+		item  string
+		score int
+	}
+	var matches []match
+	for _, it := range items {
+		if score, ok := fuzzyScore(query, it); ok {
+			matches = append(matches, match{it, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].item < matches[j].item
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.item
+	}
+	return result
+}
@@ -3,23 +3,28 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"path"
 	"strings"
 	"syscall"
 
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
 	"github.com/vitoramaral10/driver-organizer/internal/classifier"
 	"github.com/vitoramaral10/driver-organizer/internal/config"
 	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/journal"
 )
 
-func newOrganizeCmd() *cobra.Command {
+func newOrganizeCmd(classifierFlags *pflag.FlagSet) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "organize",
 		Short: "Organiza os arquivos do Google Drive",
@@ -28,18 +33,14 @@ usando IA para sugerir a melhor pasta para cada arquivo.`,
 		RunE: runOrganize,
 	}
 
-	cmd.Flags().String("gemini-api-key", "", "API key do Google AI Studio para Gemini")
-	cmd.Flags().String("gemini-model", "gemini-2.0-flash", "modelo Gemini a usar")
-	cmd.Flags().String("backup-folder", "backup", "pasta de backup")
-	cmd.Flags().Int("batch-size", 20, "arquivos por lote de classificação")
-	cmd.Flags().Float64("max-cost", 5.0, "custo máximo estimado em USD")
+	cmd.PersistentFlags().AddFlagSet(classifierFlags)
 	cmd.Flags().Bool("resume", false, "continua organizacao a partir da pasta de backup")
+	cmd.Flags().Bool("auto", false, "aplica sem perguntar as sugestões com confiança mínima; o resto fica no backup")
+	cmd.Flags().String("ui", "plain", "modo da revisão: plain (perguntas linha a linha) ou tui (tela cheia)")
+	viper.BindPFlag("ui", cmd.Flags().Lookup("ui"))
 
-	viper.BindPFlag("gemini_api_key", cmd.Flags().Lookup("gemini-api-key"))
-	viper.BindPFlag("gemini_model", cmd.Flags().Lookup("gemini-model"))
-	viper.BindPFlag("backup_folder", cmd.Flags().Lookup("backup-folder"))
-	viper.BindPFlag("batch_size", cmd.Flags().Lookup("batch-size"))
-	viper.BindPFlag("max_cost", cmd.Flags().Lookup("max-cost"))
+	cmd.AddCommand(newOrganizePlanCmd())
+	cmd.AddCommand(newOrganizeApplyCmd())
 
 	return cmd
 }
@@ -67,22 +68,62 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	auto, err := cmd.Flags().GetBool("auto")
+	if err != nil {
+		return err
+	}
+	if auto {
+		if cfg.MinConfidence < 0 || cfg.MinConfidence > 1 {
+			return fmt.Errorf("--min-confidence deve estar entre 0 e 1")
+		}
+		fmt.Printf("🤖 MODO AUTOMÁTICO: aplicando sugestões com confiança ≥ %.0f%%\n\n", cfg.MinConfidence*100)
+	}
 
-	// === SETUP: Verificar API key do Gemini ===
-	if err := ensureGeminiAPIKey(); err != nil {
+	useTUI := false
+	if !auto {
+		switch cfg.UI {
+		case "plain":
+		case "tui":
+			// Terminais sem suporte a cursor continuam no modo texto, que
+			// também aceita as respostas por um pipe
+			if t := os.Getenv("TERM"); t == "" || t == "dumb" || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+				fmt.Println("⚠️  Terminal sem suporte à tela cheia, usando o modo texto")
+			} else {
+				useTUI = true
+			}
+		default:
+			return fmt.Errorf("modo de revisão desconhecido '%s' (use plain ou tui)", cfg.UI)
+		}
+	}
+
+	// === SETUP: Verificar provedor de IA (API key do Gemini, se for o caso) ===
+	if err := prepareProvider(); err != nil {
 		return err
 	}
 
-	// === SETUP: Verificar autenticação Drive ===
-	fmt.Println("📁 Conectando ao Google Drive...")
-	srv, err := drive.NewService(ctx, cfg.CredentialsPath, cfg.TokenPath)
+	// === SETUP: Conectar ao backend (Drive ou diretório local) ===
+	backend, err := openBackend(ctx, cmd.OutOrStdout())
 	if err != nil {
 		return err
 	}
 
+	// === SETUP: Registrar operações no diário para permitir undo ===
+	var sessionID string
+	if !dryRun {
+		j, err := journal.Open(config.JournalPath())
+		if err != nil {
+			return err
+		}
+		defer j.Close()
+
+		sessionID = journal.NewSessionID()
+		backend = journal.NewRecorder(backend, j, sessionID, backendLabel(backend))
+		fmt.Printf("📝 Sessão %s (desfaça com: driver-organizer undo --session %s)\n", sessionID, sessionID)
+	}
+
 	// === ETAPA 2: Listar arquivos na raiz ===
-	fmt.Println("📋 Listando arquivos na raiz do Drive...")
-	allFiles, err := drive.ListAllFiles(ctx, srv)
+	fmt.Println("📋 Listando arquivos na raiz...")
+	allFiles, err := drive.ListAllFiles(ctx, backend)
 	if err != nil {
 		return fmt.Errorf("erro ao listar arquivos: %w", err)
 	}
@@ -103,7 +144,7 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	// === ETAPA 3: Criar pasta de backup e mover arquivos ===
 	fmt.Printf("📦 Criando pasta de backup '%s'...\n", cfg.BackupFolder)
 
-	backupFolder, err := drive.FindOrCreateNestedFolder(ctx, srv, cfg.BackupFolder, "root")
+	backupFolder, err := drive.FindOrCreateNestedFolder(ctx, backend, cfg.BackupFolder, backend.RootID())
 	if err != nil {
 		return fmt.Errorf("erro ao criar pasta de backup: %w", err)
 	}
@@ -111,12 +152,18 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	// Filtrar: não mover a própria pasta de backup
 	backupRootName := strings.Split(cfg.BackupFolder, "/")[0]
 	var filesToBackup []*drive.FileInfo
+	locked := 0
 	
 	// Incluir arquivos
 	for _, f := range files {
 		if f.Name == backupRootName && f.IsFolder() {
 			continue
 		}
+		// Em drives compartilhados, o papel do usuário pode impedir a movimentação
+		if !f.CanMove() {
+			locked++
+			continue
+		}
 		filesToBackup = append(filesToBackup, f)
 	}
 	
@@ -125,12 +172,22 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		if f.Name == backupRootName {
 			continue
 		}
+		if !f.CanMove() {
+			locked++
+			continue
+		}
 		filesToBackup = append(filesToBackup, f)
 	}
+	if locked > 0 {
+		fmt.Printf("   ⚠️  %d itens sem permissão para mover serão ignorados\n", locked)
+	}
+
+	// Pasta de origem de cada arquivo, usada pelas regras da configuração
+	folderOf := make(map[string]string)
 
 	if resume {
 		fmt.Println("↩️  Modo continuar: usando arquivos da pasta de backup. Itens na raiz não serão movidos.")
-		backupFiles, err := drive.ListAllFilesRecursive(ctx, srv, backupFolder.ID)
+		backupFiles, err := listBackupFiles(ctx, backend, backupFolder.ID, folderOf)
 		if err != nil {
 			return fmt.Errorf("erro ao listar arquivos no backup: %w", err)
 		}
@@ -145,9 +202,9 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	} else {
 		// Se não há nada para mover, verificar se há arquivos no backup para organizar
 		if len(filesToBackup) == 0 {
-			fmt.Println("   Nenhum item novo na raiz. Verificando pasta de backup recursivamente...\n")
+			fmt.Print("   Nenhum item novo na raiz. Verificando pasta de backup recursivamente...\n\n")
 
-			backupFiles, err := drive.ListAllFilesRecursive(ctx, srv, backupFolder.ID)
+			backupFiles, err := listBackupFiles(ctx, backend, backupFolder.ID, folderOf)
 			if err != nil {
 				return fmt.Errorf("erro ao listar arquivos no backup: %w", err)
 			}
@@ -175,18 +232,22 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 					}),
 				)
 
-				for _, f := range filesToBackup {
+				for i, f := range filesToBackup {
 					if ctx.Err() != nil {
 						return fmt.Errorf("operação cancelada")
 					}
 
-					oldParent := "root"
+					oldParent := backend.RootID()
 					if len(f.Parents) > 0 {
 						oldParent = f.Parents[0]
 					}
 
-					if err := drive.MoveFile(ctx, srv, f.ID, backupFolder.ID, oldParent); err != nil {
+					moved, err := backend.MoveFile(ctx, f.ID, backupFolder.ID, oldParent)
+					if err != nil {
 						slog.Error("falha ao mover para backup", "file", f.Name, "error", err)
+					} else {
+						// Manter ID e parents atualizados para a etapa de organização
+						filesToBackup[i] = moved
 					}
 
 					bar.Add(1)
@@ -197,19 +258,23 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 					fmt.Printf("   [DRY-RUN] Moveria: %s → %s\n", f.Name, cfg.BackupFolder)
 				}
 			}
+			// Para as regras, vale a raiz, de onde os arquivos vieram
+			for _, f := range filesToBackup {
+				folderOf[f.ID] = ""
+			}
 		}
 	}
 
 	// === ETAPA 4: Inicializar classificador IA ===
 	fmt.Println("\n🤖 Inicializando classificador IA...")
-	cls, err := classifier.NewClassifier(ctx, cfg.GeminiAPIKey, cfg.GeminiModel)
+	cls, err := openClassifier(ctx)
 	if err != nil {
 		return err
 	}
 	defer cls.Close()
 
 	// Coletar nomes de pastas existentes na raiz
-	rootFolders, err := drive.ListFolders(ctx, srv, "root")
+	rootFolders, err := backend.ListFolders(ctx, backend.RootID())
 	if err != nil {
 		slog.Warn("erro ao listar pastas existentes", "error", err)
 	}
@@ -223,13 +288,22 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		slog.Info("pastas existentes carregadas", "count", len(existingFolderNames), "folders", existingFolderNames)
 	}
 
-	// Cache de classificações
-	cache := classifier.NewCache()
+	// Cache de classificações, persistido entre execuções
+	cache, err := classifier.OpenCache(config.CachePath(), cls.ModelName())
+	if err != nil {
+		slog.Warn("cache persistente indisponível, usando cache em memória", "error", err)
+		cache = classifier.NewCache()
+	}
+	defer cache.Close()
+
+	// Download de conteúdo para arquivos que a IA não consegue classificar só
+	// pelo nome
+	fetcher := newContentFetcher(backend)
 
 	// Filtrar apenas arquivos para organização (pastas ficam no backup)
 	var filesToOrganize []*drive.FileInfo
 	for _, f := range filesToBackup {
-		if !f.IsFolder() {
+		if !f.IsFolder() && f.CanMove() {
 			filesToOrganize = append(filesToOrganize, f)
 		}
 	}
@@ -241,19 +315,50 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 
 	// === ETAPA 5: Classificar e organizar arquivos ===
 	fmt.Printf("\n🗂️  Iniciando organização de %d arquivos...\n", len(filesToOrganize))
-	fmt.Println("   Para cada arquivo, você pode:")
-	fmt.Println("   (m) Mover para pasta sugerida (e renomear se sugerido)")
-	fmt.Println("   (d) Descrever o arquivo para a IA reanalisar")
-	fmt.Println("   (r) Renomear a pasta de destino")
-	fmt.Println("   (n) Alterar o nome do arquivo")
-	fmt.Println("   (c) Criar nova pasta personalizada")
-	fmt.Println("   (p) Pular arquivo")
-	fmt.Println("   (q) Sair")
+	if !auto && !useTUI {
+		fmt.Println("   Para cada arquivo, você pode:")
+		fmt.Println("   (m) Mover para pasta sugerida (e renomear se sugerido)")
+		fmt.Println("   (d) Descrever o arquivo para a IA reanalisar")
+		fmt.Println("   (v) Ver o conteúdo: a IA reanalisa lendo o arquivo")
+		fmt.Println("   (r) Renomear a pasta de destino")
+		fmt.Println("   (n) Alterar o nome do arquivo")
+		fmt.Println("   (c) Criar nova pasta personalizada")
+		fmt.Println("   (p) Pular arquivo")
+		fmt.Println("   (q) Sair")
+	}
 	fmt.Println()
 
+	// Classificar em lotes, em segundo plano, à frente da revisão
+	pf := classifier.NewPrefetcher(ctx, cls, cache, prefetchItems(filesToOrganize, folderOf), existingFolderNames, cfg.BatchSize, 1)
+	sess := &reviewSession{
+		backend:       backend,
+		cls:           cls,
+		cache:         cache,
+		pf:            pf,
+		fetcher:       fetcher,
+		defaultParent: backupFolder.ID,
+		dryRun:        dryRun,
+		folders:       existingFolderNames,
+	}
+
+	if useTUI {
+		res, err := runReviewTUI(ctx, filesToOrganize, sess)
+		if err != nil {
+			return err
+		}
+		if res.quit {
+			fmt.Printf("\n✅ Organização encerrada. %d organizados, %d pulados.\n", res.organized, res.skipped)
+			printCostSummary(cls.Costs())
+			return nil
+		}
+		printOrganizeSummary(res.organized, res.skipped, res.deferred, len(filesToOrganize), cls.Costs(), sessionID)
+		return nil
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	organized := 0
 	skipped := 0
+	var deferred []*drive.FileInfo
 
 	fileLoop:
 	for i, f := range filesToOrganize {
@@ -266,50 +371,59 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		fmt.Printf("📄 [%d/%d] %s\n", i+1, len(filesToBackup), f.Name)
 		fmt.Printf("   Tipo: %s | Tamanho: %s | Criado: %s\n", f.MimeType, formatSize(f.Size), f.CreatedTime)
 
-		// Verificar cache
-		cacheKey := classifier.CacheKey(f.Name, f.MimeType)
-		suggestion := cache.Get(cacheKey)
-
-		if suggestion == nil {
-			// Classificar com IA
-			meta := classifier.FileMetadata{
-				Name:         f.Name,
-				MimeType:     f.MimeType,
-				Size:         f.Size,
-				CreatedTime:  f.CreatedTime,
-				ModifiedTime: f.ModifiedTime,
-			}
-
-			var err error
-			suggestion, err = cls.ClassifySingle(ctx, meta, existingFolderNames)
-			if err != nil {
-				slog.Error("erro na classificação", "file", f.Name, "error", err)
-				fmt.Printf("   ❌ Erro ao classificar: %v\n", err)
-				fmt.Printf("   Pulando arquivo...\n\n")
-				skipped++
-				continue
+		suggestion, err := pf.Get(ctx, i)
+		if errors.Is(err, classifier.ErrBudgetExceeded) {
+			costs := cls.Costs()
+			fmt.Printf("\n   💰 Orçamento de $%.2f atingido (gasto: $%.4f).\n", costs.Limit(), costs.Total())
+			if auto || !confirmBudgetIncrease(reader, costs) {
+				fmt.Printf("   Os %d arquivos restantes ficam no backup.\n\n", len(filesToOrganize)-i)
+				deferred = append(deferred, filesToOrganize[i:]...)
+				break fileLoop
 			}
-
-			cache.Set(cacheKey, suggestion)
+			pf.Resume()
+			suggestion, err = pf.Get(ctx, i)
 		}
-
-		fmt.Printf("\n   🤖 Sugestão da IA:\n")
-		fmt.Printf("      Pasta: %s\n", suggestion.SuggestedFolder)
-		if suggestion.SuggestedName != "" && suggestion.SuggestedName != f.Name {
-			fmt.Printf("      Nome: %s → %s\n", f.Name, suggestion.SuggestedName)
+		if err != nil {
+			slog.Error("erro na classificação", "file", f.Name, "error", err)
+			fmt.Printf("   ❌ Erro ao classificar: %v\n", err)
+			fmt.Printf("   Pulando arquivo...\n\n")
+			skipped++
+			continue
 		}
-		fmt.Printf("      Motivo: %s\n", suggestion.Reason)
-		fmt.Printf("      Confiança: %.0f%%\n", suggestion.Confidence*100)
 
+		if suggestion.Rule != "" {
+			printSuggestion("Regra da configuração", f, suggestion)
+		} else {
+			printSuggestion("Sugestão da IA", f, suggestion)
+		}
 		if suggestion.NeedsContent {
 			fmt.Printf("      ⚠️  IA sugere analisar conteúdo para melhor classificação\n")
+			suggestion = reclassifyWithContent(ctx, sess, f, suggestion)
+		}
+		if costs := cls.Costs(); costs != nil && costs.Total() > 0 {
+			fmt.Printf("      💰 Custo da sessão: %s\n", formatCost(costs))
 		}
 
 		var targetFolder string
 		var targetName string
 
-		for {
-			fmt.Printf("\n   Ação? (m)over / (d)escrever / (r)enomear pasta / (n)omear arquivo / (c)riar nova / (p)ular / (q)uit: ")
+		// No modo automático, só a confiança decide; o resto fica no backup
+		// para uma passada interativa depois (organize --resume)
+		if auto {
+			if suggestion.Confidence < cfg.MinConfidence || suggestion.SuggestedFolder == "" {
+				fmt.Printf("   ⏸️  Adiado: confiança abaixo de %.0f%%, fica no backup\n\n", cfg.MinConfidence*100)
+				deferred = append(deferred, f)
+				continue
+			}
+			targetFolder = suggestion.SuggestedFolder
+			targetName = f.Name
+			if suggestion.SuggestedName != "" {
+				targetName = suggestion.SuggestedName
+			}
+		}
+
+		for !auto {
+			fmt.Printf("\n   Ação? (m)over / (d)escrever / (v)er conteúdo / (r)enomear pasta / (n)omear arquivo / (c)riar nova / (p)ular / (q)uit: ")
 
 			input, _ := reader.ReadString('\n')
 			input = strings.TrimSpace(strings.ToLower(input))
@@ -334,22 +448,12 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 				} else {
 					fmt.Println("   🤖 Reanalisando com sua descrição...")
 					
-					meta := classifier.FileMetadata{
-						Name:         f.Name,
-						MimeType:     f.MimeType,
-						Size:         f.Size,
-						CreatedTime:  f.CreatedTime,
-						ModifiedTime: f.ModifiedTime,
-					}
-					
-					newSuggestion, err := cls.ClassifyWithDescription(ctx, meta, description, existingFolderNames)
+					newSuggestion, err := sess.Describe(ctx, f, description)
 					if err != nil {
-						slog.Error("erro na reclassificação", "file", f.Name, "error", err)
 						fmt.Printf("   ❌ Erro ao reclassificar: %v\n", err)
 						fmt.Println("   Mantendo sugestão original.")
 					} else {
 						suggestion = newSuggestion
-						cache.Set(cacheKey, suggestion)
 						
 						fmt.Printf("\n   🤖 Nova sugestão:\n")
 						fmt.Printf("      Pasta: %s\n", suggestion.SuggestedFolder)
@@ -363,6 +467,10 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 				// Repergunta a ação sem mover automaticamente
 				continue
 
+			case "v":
+				suggestion = reclassifyWithContent(ctx, sess, f, suggestion)
+				continue
+
 			case "r":
 			fmt.Printf("   Novo nome da pasta [%s]: ", suggestion.SuggestedFolder)
 			newFolder, _ := reader.ReadString('\n')
@@ -417,6 +525,7 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 
 		case "q":
 			fmt.Printf("\n✅ Organização encerrada. %d organizados, %d pulados.\n", organized, skipped)
+			printCostSummary(cls.Costs())
 			return nil
 
 		default:
@@ -428,65 +537,96 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		}
 
 		// Executar a ação de mover/renomear
-		destFolder, err := drive.FindOrCreateNestedFolder(ctx, srv, targetFolder, "root")
+		movedName, err := sess.Move(ctx, f, targetFolder, targetName)
 		if err != nil {
-			slog.Error("erro ao criar pasta destino", "folder", targetFolder, "error", err)
-			fmt.Printf("   ❌ Erro ao criar pasta: %v\n", err)
+			fmt.Printf("   ❌ %v\n", err)
 			skipped++
 			continue
 		}
-
-		// Obter o parent atual do arquivo
-		oldParent := backupFolder.ID
-		if len(f.Parents) > 0 {
-			oldParent = f.Parents[0]
-		}
-
-		// Se mudou o nome, fazer move + rename
-		if targetName != f.Name {
-			if err := drive.MoveAndRenameFile(ctx, srv, f.ID, targetName, destFolder.ID, oldParent); err != nil {
-				slog.Error("erro ao mover e renomear arquivo", "file", f.Name, "error", err)
-				fmt.Printf("   ❌ Erro ao mover: %v\n", err)
-				skipped++
-				continue
-			}
-			fmt.Printf("   ✅ Renomeado para: %s\n", targetName)
-			fmt.Printf("   ✅ Movido para: %s\n", targetFolder)
-		} else {
-			// Apenas mover
-			if err := drive.MoveFile(ctx, srv, f.ID, destFolder.ID, oldParent); err != nil {
-				slog.Error("erro ao mover arquivo", "file", f.Name, "error", err)
-				fmt.Printf("   ❌ Erro ao mover: %v\n", err)
-				skipped++
-				continue
-			}
-			fmt.Printf("   ✅ Movido para: %s\n", targetFolder)
-		}
-
-		// Adicionar pasta à lista de existentes se for nova
-		isNew := true
-		for _, name := range existingFolderNames {
-			if name == targetFolder {
-				isNew = false
-				break
-			}
+		if dryRun {
+			fmt.Printf("   [DRY-RUN] Moveria: %s → %s\n", f.Name, path.Join(targetFolder, targetName))
+			organized++
+			fmt.Println()
+			continue
 		}
-		if isNew {
-			existingFolderNames = append(existingFolderNames, targetFolder)
-			slog.Debug("pasta adicionada ao histórico", "folder", targetFolder)
+		if movedName != f.Name {
+			fmt.Printf("   ✅ Renomeado para: %s\n", movedName)
 		}
+		fmt.Printf("   ✅ Movido para: %s\n", targetFolder)
 
 		organized++
 		fmt.Println()
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	printOrganizeSummary(organized, skipped, deferred, len(filesToOrganize), cls.Costs(), sessionID)
+	return nil
+}
+
+// printOrganizeSummary mostra o resultado da revisão, os arquivos adiados e o
+// custo da sessão.
+func printOrganizeSummary(organized, skipped int, deferred []*drive.FileInfo, total int, costs *classifier.CostTracker, sessionID string) {
 	fmt.Printf("\n🎉 Organização concluída!\n")
 	fmt.Printf("   ✅ Organizados: %d\n", organized)
 	fmt.Printf("   ⏭️  Pulados: %d\n", skipped)
-	fmt.Printf("   📁 Total: %d\n", len(filesToOrganize))
+	if len(deferred) > 0 {
+		fmt.Printf("   ⏸️  Adiados: %d\n", len(deferred))
+	}
+	fmt.Printf("   📁 Total: %d\n", total)
+	if len(deferred) > 0 {
+		fmt.Printf("\n⏸️  Arquivos adiados (continuam em '%s'):\n", cfg.BackupFolder)
+		for _, f := range deferred {
+			fmt.Printf("   - %s\n", f.Name)
+		}
+		fmt.Println("\n   Revise-os interativamente com: driver-organizer organize --resume")
+	}
+	printCostSummary(costs)
+	if sessionID != "" {
+		fmt.Printf("\n↩️  Para desfazer: driver-organizer undo --session %s\n", sessionID)
+	}
+}
 
-	return nil
+// isTerminal informa se f é um terminal interativo (e não um pipe ou arquivo).
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// prefetchItems prepara os arquivos para o Prefetcher, com a chave de cache
+// de cada um. folderOf informa a pasta de cada arquivo (pelo ID), avaliada
+// pelas regras.
+func prefetchItems(files []*drive.FileInfo, folderOf map[string]string) []classifier.PrefetchItem {
+	items := make([]classifier.PrefetchItem, len(files))
+	for i, f := range files {
+		meta := fileMetadata(f)
+		meta.Folder = folderOf[f.ID]
+		items[i] = classifier.PrefetchItem{
+			File:     meta,
+			CacheKey: classifier.CacheKey(f.Md5Checksum, f.Name, f.Size, f.MimeType),
+		}
+	}
+	return items
+}
+
+// listBackupFiles lista recursivamente os arquivos da pasta de backup e
+// registra em folderOf a pasta de cada um.
+func listBackupFiles(ctx context.Context, backend drive.Backend, backupFolderID string, folderOf map[string]string) ([]*drive.FileInfo, error) {
+	var files []*drive.FileInfo
+	err := drive.WalkFiles(ctx, backend, backupFolderID, cfg.BackupFolder, func(f *drive.FileInfo, p string) {
+		files = append(files, f)
+		folderOf[f.ID] = path.Dir(p)
+	})
+	return files, err
+}
+
+// fileMetadata converte f nos metadados enviados ao classificador.
+func fileMetadata(f *drive.FileInfo) classifier.FileMetadata {
+	return classifier.FileMetadata{
+		Name:         f.Name,
+		MimeType:     f.MimeType,
+		Size:         f.Size,
+		CreatedTime:  f.CreatedTime,
+		ModifiedTime: f.ModifiedTime,
+	}
 }
 
 func formatSize(bytes int64) string {
@@ -524,7 +664,11 @@ func ensureGeminiAPIKey() error {
 		return nil
 	}
 
-	// 3. Pedir ao usuário
+	// 3. Pedir ao usuário, se houver um terminal
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("gemini_api_key não configurada (use --gemini-api-key, DORGANIZER_GEMINI_API_KEY ou config.yaml)")
+	}
+
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println("🔑 Configuração da API Key do Gemini")
 	fmt.Println()
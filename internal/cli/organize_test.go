@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+)
+
+// useMemoryBackend isola a configuração do teste em um HOME temporário e faz
+// os comandos usarem m no lugar do Drive. Como o backend real, ele anuncia a
+// conexão em out.
+func useMemoryBackend(t *testing.T, m *drive.MemoryBackend) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	orig := openBackend
+	openBackend = func(ctx context.Context, out io.Writer) (drive.Backend, error) {
+		fmt.Fprintln(out, "📁 Usando backend em memória")
+		return m, nil
+	}
+	t.Cleanup(func() { openBackend = orig })
+}
+
+func run(t *testing.T, args ...string) {
+	t.Helper()
+	cmd := NewRootCmd()
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+}
+
+// assertIn falha se o item id não estiver dentro da pasta folderPath.
+func assertIn(t *testing.T, m *drive.MemoryBackend, id, folderPath string) {
+	t.Helper()
+	ctx := context.Background()
+
+	want := m.RootID()
+	if folderPath != "" {
+		folder, err := drive.FindNestedFolder(ctx, m, folderPath, m.RootID())
+		if err != nil || folder == nil {
+			t.Fatalf("pasta %s não encontrada: %v", folderPath, err)
+		}
+		want = folder.ID
+	}
+
+	f, err := m.GetFile(ctx, id)
+	if err != nil {
+		t.Fatalf("GetFile(%s): %v", id, err)
+	}
+	if f.Trashed || !slices.Contains(f.Parents, want) {
+		t.Errorf("%s está em %v (lixeira: %v), want %q", f.Name, f.Parents, f.Trashed, folderPath)
+	}
+}
+
+// sampleDrive monta uma raiz com arquivos de confiança alta e baixa e uma
+// pasta com conteúdo.
+func sampleDrive() (m *drive.MemoryBackend, boleto, screenshot, notes, old *drive.FileInfo) {
+	m = drive.NewMemoryBackend()
+	m.SetPageSize(2)
+	boleto = m.AddFile("boleto_luz.pdf", "application/pdf", 100)
+	screenshot = m.AddFile("screenshot 2024-01-10.png", "image/png", 200)
+	notes = m.AddFile("notas.txt", "text/plain", 10)
+	old = m.AddFolder("Antigo")
+	m.AddFile("rascunho.txt", "text/plain", 10, old.ID)
+	return m, boleto, screenshot, notes, old
+}
+
+func TestOrganizeAutoAndUndo(t *testing.T) {
+	m, boleto, screenshot, notes, old := sampleDrive()
+	useMemoryBackend(t, m)
+
+	run(t, "organize", "--auto", "--classifier", "offline", "--min-confidence", "0.8")
+
+	assertIn(t, m, boleto.ID, "Financeiro/Boletos")
+	assertIn(t, m, screenshot.ID, "Fotos/Capturas de Tela")
+	// Abaixo da confiança mínima, o arquivo fica no backup para revisão
+	assertIn(t, m, notes.ID, "backup")
+	assertIn(t, m, old.ID, "backup")
+
+	run(t, "undo", "-y")
+
+	for _, id := range []string{boleto.ID, screenshot.ID, notes.ID, old.ID} {
+		assertIn(t, m, id, "")
+	}
+	for _, folder := range []string{"backup", "Financeiro", "Fotos"} {
+		if f, _ := drive.FindNestedFolder(context.Background(), m, folder, m.RootID()); f != nil {
+			t.Errorf("pasta %s criada na sessão continua fora da lixeira", folder)
+		}
+	}
+}
+
+func TestOrganizeDryRunMovesNothing(t *testing.T) {
+	m, boleto, screenshot, notes, old := sampleDrive()
+	useMemoryBackend(t, m)
+
+	run(t, "organize", "--auto", "--dry-run", "--classifier", "offline", "--min-confidence", "0.8")
+
+	for _, id := range []string{boleto.ID, screenshot.ID, notes.ID, old.ID} {
+		assertIn(t, m, id, "")
+	}
+	for _, folder := range []string{"Financeiro", "Fotos"} {
+		if f, _ := drive.FindNestedFolder(context.Background(), m, folder, m.RootID()); f != nil {
+			t.Errorf("dry-run criou a pasta %s", folder)
+		}
+	}
+}
+
+func TestOrganizePlanAndApply(t *testing.T) {
+	m, boleto, screenshot, notes, old := sampleDrive()
+	useMemoryBackend(t, m)
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	run(t, "organize", "plan", "-o", planPath, "--classifier", "offline", "--min-confidence", "0.8")
+
+	p, err := plan.Load(planPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// Pastas da raiz não entram no plano
+	if len(p.Entries) != 3 {
+		t.Fatalf("plano com %d entradas, want 3", len(p.Entries))
+	}
+	// O plano não move nada
+	assertIn(t, m, boleto.ID, "")
+	// Abaixo da confiança mínima, a entrada vem sem aprovação
+	for _, e := range p.Entries {
+		if want := e.FileID != notes.ID; e.Approved != want {
+			t.Errorf("%s: approved = %v, want %v (confiança %.2f)", e.CurrentPath, e.Approved, want, e.Confidence)
+		}
+	}
+
+	// Editar o plano à mão: o destino da captura de tela muda
+	for i, e := range p.Entries {
+		if e.FileID == screenshot.ID {
+			p.Entries[i].TargetFolder = "Trabalho/Telas"
+			p.Entries[i].Approved = true
+		}
+	}
+	if err := p.Save(planPath); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	run(t, "organize", "apply", planPath)
+
+	assertIn(t, m, boleto.ID, "Financeiro/Boletos")
+	assertIn(t, m, screenshot.ID, "Trabalho/Telas")
+	assertIn(t, m, notes.ID, "")
+	assertIn(t, m, old.ID, "")
+
+	run(t, "undo", "-y")
+	assertIn(t, m, boleto.ID, "")
+	assertIn(t, m, screenshot.ID, "")
+}
+
+func TestDuplicatesKeepOrganized(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	useMemoryBackend(t, m)
+
+	docs := m.AddFolder("Documentos")
+	backup := m.AddFolder("backup")
+	content := []byte("mesmo contrato")
+	var copies []*drive.FileInfo
+	for _, parent := range []string{m.RootID(), docs.ID, backup.ID} {
+		f := m.AddFile("contrato.pdf", "application/pdf", int64(len(content)), parent)
+		m.SetContent(f.ID, content)
+		copies = append(copies, f)
+	}
+
+	run(t, "duplicates", "--keep", "organized", "--yes")
+
+	if m.IsTrashed(copies[1].ID) {
+		t.Error("a cópia em Documentos foi para a lixeira")
+	}
+	for _, f := range []*drive.FileInfo{copies[0], copies[2]} {
+		if !m.IsTrashed(f.ID) {
+			t.Errorf("cópia %s em %v não foi para a lixeira", f.ID, f.Parents)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/extract"
+	"github.com/vitoramaral10/driver-organizer/internal/journal"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+)
+
+func newOrganizePlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Gera um plano de organização sem mover nada",
+		Long: `Classifica os arquivos da raiz e da pasta de backup sem interação e grava
+as sugestões em um arquivo JSON. Só as sugestões com confiança mínima
+(--min-confidence) vêm aprovadas. Revise o arquivo (mude "approved", "target_folder"
+ou "target_name") e aplique com "organize apply".`,
+		Args: cobra.NoArgs,
+		RunE: runOrganizePlan,
+	}
+
+	cmd.Flags().StringP("output", "o", "plan.json", "arquivo do plano")
+
+	return cmd
+}
+
+func newOrganizeApplyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply <plan.json>",
+		Short: "Aplica as entradas aprovadas de um plano",
+		Long: `Move e renomeia os arquivos das entradas com "approved": true. Antes de cada
+movimentação o arquivo é conferido com o estado atual do Drive; arquivos que mudaram
+desde a geração do plano são pulados. As operações ficam no diário e podem ser
+desfeitas com "undo".`,
+		Args: cobra.ExactArgs(1),
+		RunE: runOrganizeApply,
+	}
+}
+
+// signalContext retorna um contexto cancelado ao receber SIGINT/SIGTERM.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			fmt.Println("\n\n⚠️  Interrupção recebida, encerrando de forma segura...")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigCh)
+	}()
+
+	return ctx, cancel
+}
+
+func runOrganizePlan(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	output, _ := cmd.Flags().GetString("output")
+
+	if err := prepareProvider(); err != nil {
+		return err
+	}
+
+	backend, err := openBackend(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	// Arquivos soltos na raiz e pendentes no backup, com o caminho atual
+	fmt.Println("📋 Listando arquivos na raiz e no backup...")
+	files, locked, err := listUnorganized(ctx, backend)
+	if err != nil {
+		return err
+	}
+
+	if locked > 0 {
+		fmt.Printf("   ⚠️  %d arquivos sem permissão para mover serão ignorados\n", locked)
+	}
+	if len(files) == 0 {
+		fmt.Println("✅ Nenhum arquivo para organizar!")
+		return nil
+	}
+	fmt.Printf("   Encontrados: %d arquivos\n\n", len(files))
+
+	fmt.Println("🤖 Inicializando classificador IA...")
+	cls, err := openClassifier(ctx)
+	if err != nil {
+		return err
+	}
+	defer cls.Close()
+
+	var existingFolderNames []string
+	rootFolders, err := backend.ListFolders(ctx, backend.RootID())
+	if err != nil {
+		slog.Warn("erro ao listar pastas existentes", "error", err)
+	}
+	for _, f := range rootFolders {
+		existingFolderNames = append(existingFolderNames, f.Name)
+	}
+
+	cache, err := classifier.OpenCache(config.CachePath(), cls.ModelName())
+	if err != nil {
+		slog.Warn("cache persistente indisponível, usando cache em memória", "error", err)
+		cache = classifier.NewCache()
+	}
+	defer cache.Close()
+
+	p := &plan.Plan{
+		Version:   plan.Version,
+		CreatedAt: time.Now().UTC(),
+		Backend:   backendLabel(backend),
+		Model:     cls.ModelName(),
+	}
+
+	bar := progressbar.NewOptions(len(files),
+		progressbar.OptionSetDescription("   Classificando"),
+		progressbar.OptionSetWidth(40),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "█",
+			SaucerPadding: "░",
+			BarStart:      "[",
+			BarEnd:        "]",
+		}),
+	)
+
+	fileInfos := make([]*drive.FileInfo, len(files))
+	folderOf := make(map[string]string, len(files))
+	for i, lf := range files {
+		fileInfos[i] = lf.file
+		folderOf[lf.file.ID] = parentPath(lf.path)
+	}
+	// Sem revisão interativa, todos os lotes podem ser buscados de uma vez
+	pf := classifier.NewPrefetcher(ctx, cls, cache, prefetchItems(fileInfos, folderOf), existingFolderNames, cfg.BatchSize, len(files))
+
+	fetcher := newContentFetcher(backend)
+	failed := 0
+	budgetHit := false
+	for i, lf := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		suggestion, err := pf.Get(ctx, i)
+		if errors.Is(err, classifier.ErrBudgetExceeded) {
+			budgetHit = true
+			break
+		}
+		if costs := cls.Costs(); costs != nil && costs.Total() > 0 {
+			bar.Describe(fmt.Sprintf("   Classificando (%s)", formatCost(costs)))
+		}
+		bar.Add(1)
+		if err != nil {
+			slog.Error("erro na classificação", "file", lf.file.Name, "error", err)
+			failed++
+			continue
+		}
+
+		if suggestion.NeedsContent {
+			if s, err := classifyWithContent(ctx, fetcher, cls, cache, lf.file, existingFolderNames); err == nil {
+				suggestion = s
+				if !s.Offline {
+					cache.Set(classifier.CacheKey(lf.file.Md5Checksum, lf.file.Name, lf.file.Size, lf.file.MimeType), s)
+				}
+			} else if errors.Is(err, extract.ErrScanned) {
+				slog.Info("PDF digitalizado, sem camada de texto", "file", lf.path)
+			} else {
+				slog.Debug("classificação por conteúdo indisponível", "file", lf.file.Name, "error", err)
+			}
+		}
+
+		e := plan.NewEntry(lf.file, lf.path)
+		e.TargetFolder = suggestion.SuggestedFolder
+		e.TargetName = suggestion.SuggestedName
+		e.Reason = suggestion.Reason
+		e.Confidence = suggestion.Confidence
+		// Como na fila do watch, só as sugestões confiáveis já vêm aprovadas
+		e.Approved = e.TargetFolder != "" && e.Confidence >= cfg.MinConfidence
+		p.Entries = append(p.Entries, e)
+		pf.AddFolder(e.TargetFolder)
+	}
+	fmt.Println()
+
+	if err := p.Save(output); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n📝 Plano salvo em %s\n", output)
+	approved := 0
+	for _, e := range p.Entries {
+		if e.Approved {
+			approved++
+		}
+	}
+	fmt.Printf("   Entradas: %d (%d aprovadas com confiança ≥ %.0f%%)\n", len(p.Entries), approved, cfg.MinConfidence*100)
+	if failed > 0 {
+		fmt.Printf("   ❌ Não classificados: %d\n", failed)
+	}
+	if budgetHit {
+		fmt.Printf("   💰 Orçamento de $%.2f atingido: %d arquivos ficaram fora do plano (aumente --max-cost).\n",
+			cls.Costs().Limit(), len(files)-len(p.Entries)-failed)
+	}
+	if ctx.Err() != nil {
+		fmt.Println("   ⚠️  Interrompido: o plano contém apenas os arquivos já classificados.")
+	}
+	printCostSummary(cls.Costs())
+	fmt.Printf("\n   Revise o arquivo e aplique com: driver-organizer organize apply %s\n", output)
+	return nil
+}
+
+func runOrganizeApply(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	p, err := plan.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	dryRun := cfg.DryRun
+	if dryRun {
+		fmt.Println("🔍 MODO DRY-RUN: nenhum arquivo será movido")
+		fmt.Println()
+	}
+
+	backend, err := openBackendByLabel(ctx, p.Backend, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	var sessionID string
+	if !dryRun {
+		j, err := journal.Open(config.JournalPath())
+		if err != nil {
+			return err
+		}
+		defer j.Close()
+
+		sessionID = journal.NewSessionID()
+		backend = journal.NewRecorder(backend, j, sessionID, p.Backend)
+	}
+
+	fmt.Printf("🗂️  Aplicando plano %s (%d entradas)...\n\n", args[0], len(p.Entries))
+
+	applied, notApproved, stale, failed := 0, 0, 0, 0
+	for _, e := range p.Entries {
+		if ctx.Err() != nil {
+			fmt.Println("\n⚠️  Operação cancelada.")
+			break
+		}
+		if !e.Approved {
+			notApproved++
+			continue
+		}
+
+		err := e.Apply(ctx, backend, dryRun)
+		switch {
+		case errors.Is(err, plan.ErrStale):
+			fmt.Printf("   ⚠️  %s: %v, pulando\n", e.CurrentPath, err)
+			stale++
+		case err != nil:
+			fmt.Printf("   ❌ %s: %v\n", e.CurrentPath, err)
+			slog.Error("falha ao aplicar entrada do plano", "file", e.CurrentPath, "error", err)
+			failed++
+		case dryRun:
+			fmt.Printf("   [DRY-RUN] Moveria: %s → %s\n", e.CurrentPath, e.TargetPath())
+			applied++
+		default:
+			fmt.Printf("   ✅ %s → %s\n", e.CurrentPath, e.TargetPath())
+			applied++
+		}
+	}
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("\n🎉 Plano aplicado!\n")
+	fmt.Printf("   ✅ Aplicados: %d\n", applied)
+	fmt.Printf("   ⏭️  Não aprovados: %d\n", notApproved)
+	fmt.Printf("   ⚠️  Alterados desde o plano: %d\n", stale)
+	if failed > 0 {
+		fmt.Printf("   ❌ Erros: %d\n", failed)
+	}
+	if sessionID != "" && applied > 0 {
+		fmt.Printf("\n↩️  Para desfazer: driver-organizer undo --session %s\n", sessionID)
+	}
+	return nil
+}
+
+// located é um arquivo com o caminho dele a partir da raiz.
+type located struct {
+	file *drive.FileInfo
+	path string
+}
+
+// listUnorganized lista os arquivos soltos na raiz e os pendentes na pasta de
+// backup. Arquivos sem permissão para mover ficam de fora e são contados em
+// locked.
+func listUnorganized(ctx context.Context, backend drive.Backend) (files []located, locked int, err error) {
+	rootFiles, err := drive.ListAllFiles(ctx, backend)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar arquivos: %w", err)
+	}
+	for _, f := range rootFiles {
+		switch {
+		case f.IsFolder():
+		case !f.CanMove():
+			locked++
+		default:
+			files = append(files, located{f, f.Name})
+		}
+	}
+
+	backupFolder, err := drive.FindNestedFolder(ctx, backend, cfg.BackupFolder, backend.RootID())
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar pasta de backup: %w", err)
+	}
+	if backupFolder != nil {
+		err := drive.WalkFiles(ctx, backend, backupFolder.ID, cfg.BackupFolder, func(f *drive.FileInfo, path string) {
+			if !f.CanMove() {
+				locked++
+				return
+			}
+			files = append(files, located{f, path})
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao listar arquivos no backup: %w", err)
+		}
+	}
+	return files, locked, nil
+}
+
+// parentPath retorna a pasta de um caminho relativo à raiz, ou "" na raiz.
+func parentPath(p string) string {
+	if dir := path.Dir(p); dir != "." {
+		return dir
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+)
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Gerencia perfis de contas",
+		Long: `Perfis separam contas (ex: pessoal e trabalho): cada um tem token do Google,
+API key do Gemini, cache de classificações e diário de operações próprios, em
+~/.config/driver-organizer/profiles/<nome>. O config.yaml do perfil sobrepõe a
+configuração global (ex: backup_folder).
+
+Crie um perfil com "driver-organizer auth --profile <nome>" e use-o com
+--profile <nome> em qualquer comando.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lista os perfis",
+		Args:  cobra.NoArgs,
+		RunE:  runProfileList,
+	})
+
+	remove := &cobra.Command{
+		Use:   "remove <nome>",
+		Short: "Remove um perfil, com token, API key, cache e diário",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runProfileRemove,
+	}
+	remove.Flags().BoolP("yes", "y", false, "não pede confirmação")
+	cmd.AddCommand(remove)
+
+	return cmd
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Println("👤 Nenhum perfil criado.")
+		fmt.Println("   Crie um com: driver-organizer auth --profile <nome>")
+		return nil
+	}
+
+	fmt.Println("👤 Perfis:")
+	for _, p := range profiles {
+		marker := " "
+		if p.Name == config.ActiveProfile() {
+			marker = "*"
+		}
+		var status []string
+		if p.HasToken {
+			status = append(status, "autenticado")
+		} else {
+			status = append(status, "sem token")
+		}
+		if p.HasGeminiKey {
+			status = append(status, "API key salva")
+		}
+		if p.BackupFolder != "" {
+			status = append(status, "backup: "+p.BackupFolder)
+		}
+		fmt.Printf(" %s %s  (%s)\n", marker, p.Name, strings.Join(status, ", "))
+	}
+	return nil
+}
+
+func runProfileRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !config.ProfileExists(name) {
+		return fmt.Errorf("perfil '%s' não encontrado", name)
+	}
+
+	if !yes {
+		fmt.Printf("Remover o perfil '%s' (%s), com token, API key, cache e diário? [s/N]: ", name, config.ProfileDir(name))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "s" && answer != "sim" {
+			fmt.Println("❌ Cancelado.")
+			return nil
+		}
+	}
+
+	if err := config.RemoveProfile(name); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Perfil '%s' removido.\n", name)
+	return nil
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/report"
+)
+
+func newTreeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Mostra a hierarquia de pastas com quantidade e tamanho dos arquivos",
+		Long: `Percorre o Drive inteiro (ou a pasta de --from) e mostra a árvore de pastas
+com o total de arquivos e bytes de cada uma, incluindo as subpastas. --depth
+limita os níveis exibidos; os totais continuam contando as pastas omitidas.`,
+		Args: cobra.NoArgs,
+		RunE: runTree,
+	}
+
+	cmd.Flags().Int("depth", 2, "níveis de subpastas exibidos (0 mostra todos)")
+	cmd.Flags().String("from", "", "caminho da pasta inicial (padrão: a raiz)")
+	cmd.Flags().Bool("json", false, "imprime a árvore em JSON")
+
+	return cmd
+}
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Resume arquivos e bytes por tipo, pasta e ano",
+		Long: `Percorre o Drive inteiro (ou a pasta de --from) e agrupa a quantidade de
+arquivos e de bytes por família de MIME type (imagens, PDFs, planilhas...), por
+pasta de primeiro nível e por ano de modificação.`,
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+
+	cmd.Flags().String("from", "", "caminho da pasta inicial (padrão: a raiz)")
+	cmd.Flags().Bool("json", false, "imprime as estatísticas em JSON")
+
+	return cmd
+}
+
+// scanFrom percorre a pasta from (a raiz se vazio), escrevendo as mensagens
+// de progresso em progress.
+func scanFrom(ctx context.Context, from string, progress io.Writer) (*report.Node, error) {
+	backend, err := openBackend(ctx, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	from = strings.Trim(from, "/")
+	folderID := backend.RootID()
+	if from != "" {
+		f, err := drive.FindNestedFolder(ctx, backend, from, backend.RootID())
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			return nil, fmt.Errorf("pasta '%s' não encontrada", from)
+		}
+		folderID = f.ID
+	}
+
+	if from != "" {
+		fmt.Fprintf(progress, "📋 Listando pastas em '%s'...\n\n", from)
+	} else {
+		fmt.Fprint(progress, "📋 Listando todas as pastas...\n\n")
+	}
+
+	root, err := report.Scan(ctx, backend, folderID, from)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar arquivos: %w", err)
+	}
+	return root, nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("erro ao gerar JSON: %w", err)
+	}
+	return nil
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	from, _ := cmd.Flags().GetString("from")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if depth < 0 {
+		return fmt.Errorf("--depth deve ser 0 ou maior")
+	}
+
+	root, err := scanFrom(ctx, from, reportProgress(cmd, asJSON))
+	if err != nil {
+		return err
+	}
+	root = root.Prune(depth)
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		return printJSON(out, root)
+	}
+
+	fmt.Fprintf(out, "📁 %s  %s\n", root.Name, nodeSummary(root))
+	printTreeChildren(out, root, "")
+	return nil
+}
+
+// reportProgress retorna onde tree e stats escrevem o progresso: o stderr
+// com --json, para não se misturar ao JSON, ou a saída do comando.
+func reportProgress(cmd *cobra.Command, asJSON bool) io.Writer {
+	if asJSON {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+func printTreeChildren(w io.Writer, n *report.Node, indent string) {
+	for i, child := range n.Children {
+		branch, next := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s  %s\n", indent, branch, child.Name, nodeSummary(child))
+		printTreeChildren(w, child, indent+next)
+	}
+}
+
+func nodeSummary(n *report.Node) string {
+	if n.Files == 0 {
+		return "(vazia)"
+	}
+	return fmt.Sprintf("(%d arquivos, %s)", n.Files, formatSize(n.Size))
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	from, _ := cmd.Flags().GetString("from")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	root, err := scanFrom(ctx, from, reportProgress(cmd, asJSON))
+	if err != nil {
+		return err
+	}
+	s := report.ComputeStats(root)
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		return printJSON(out, s)
+	}
+
+	fmt.Fprintf(out, "📊 %d arquivos em %d pastas, %s no total\n", s.Files, s.Folders, formatSize(s.Size))
+	if s.Files == 0 {
+		return nil
+	}
+	printBuckets(out, "Por tipo", s.ByMimeType, s.Size)
+	printBuckets(out, "Por pasta", s.ByTopFolder, s.Size)
+	printBuckets(out, "Por ano de modificação", s.ByYear, s.Size)
+	return nil
+}
+
+func printBuckets(w io.Writer, title string, buckets []report.Bucket, total int64) {
+	width := 0
+	for _, b := range buckets {
+		width = max(width, len([]rune(b.Key)))
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, b := range buckets {
+		pct := 0.0
+		if total > 0 {
+			pct = float64(b.Size) * 100 / float64(total)
+		}
+		pad := strings.Repeat(" ", width-len([]rune(b.Key)))
+		fmt.Fprintf(w, "   %s%s  %6d arquivos  %10s  %5.1f%%\n", b.Key, pad, b.Files, formatSize(b.Size), pct)
+	}
+}
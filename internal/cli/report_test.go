@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/report"
+)
+
+func TestStatsJSONIsOnlyData(t *testing.T) {
+	m, _, _, _, _ := sampleDrive()
+	useMemoryBackend(t, m)
+
+	stdout, stderr := runOutput(t, "stats", "--json")
+
+	var s report.Stats
+	if err := json.Unmarshal([]byte(stdout), &s); err != nil {
+		t.Fatalf("stdout não é JSON: %v\n%s", err, stdout)
+	}
+	if s.Files != 4 || s.Folders != 1 {
+		t.Errorf("stats = %d arquivos em %d pastas, want 4 em 1", s.Files, s.Folders)
+	}
+	if !strings.Contains(stderr, "Listando todas as pastas") {
+		t.Errorf("progresso fora do stderr: %q", stderr)
+	}
+}
+
+func TestTreeDepth(t *testing.T) {
+	m, _, _, _, old := sampleDrive()
+	m.AddFolder("Sub", old.ID)
+	useMemoryBackend(t, m)
+
+	stdout, _ := runOutput(t, "tree", "--depth", "1")
+	if !strings.Contains(stdout, "└── Antigo  (1 arquivos, ") || strings.Contains(stdout, "Sub") {
+		t.Errorf("tree --depth 1:\n%s", stdout)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// reviewSession reúne o que a revisão dos arquivos usa, no modo texto e na
+// TUI. Na TUI, os métodos que recebem ctx rodam fora da goroutine da
+// interface; as pastas conhecidas são protegidas por mu.
+type reviewSession struct {
+	backend drive.Backend
+	cls     *classifier.Classifier
+	cache   *classifier.Cache
+	pf      *classifier.Prefetcher
+	fetcher *contentFetcher
+
+	// defaultParent é a pasta de origem dos arquivos que não informam parents
+	defaultParent string
+	dryRun        bool
+
+	mu      sync.Mutex
+	folders []string
+}
+
+// Suggest retorna a sugestão do arquivo i, na ordem da revisão.
+func (s *reviewSession) Suggest(ctx context.Context, i int) (*classifier.Suggestion, error) {
+	return s.pf.Get(ctx, i)
+}
+
+// Describe reclassifica f com a descrição do usuário.
+func (s *reviewSession) Describe(ctx context.Context, f *drive.FileInfo, description string) (*classifier.Suggestion, error) {
+	sug, err := s.cls.ClassifyWithDescription(ctx, fileMetadata(f), description, s.Folders())
+	if err != nil {
+		slog.Error("erro na reclassificação", "file", f.Name, "error", err)
+		return nil, err
+	}
+	if !sug.Offline {
+		s.cache.Set(classifier.CacheKey(f.Md5Checksum, f.Name, f.Size, f.MimeType), sug)
+	}
+	return sug, nil
+}
+
+// Content reclassifica f pelo conteúdo. PDFs sem texto resultam em
+// extract.ErrScanned.
+func (s *reviewSession) Content(ctx context.Context, f *drive.FileInfo) (*classifier.Suggestion, error) {
+	sug, err := classifyWithContent(ctx, s.fetcher, s.cls, s.cache, f, s.Folders())
+	if err != nil {
+		slog.Debug("classificação por conteúdo indisponível", "file", f.Name, "error", err)
+		return nil, err
+	}
+	if !sug.Offline {
+		s.cache.Set(classifier.CacheKey(f.Md5Checksum, f.Name, f.Size, f.MimeType), sug)
+	}
+	return sug, nil
+}
+
+// Move move f para folder, criada se preciso, com o nome name, e retorna o
+// nome final do arquivo, que o backend pode ter mudado (ex: " (1)" no backend
+// local quando o nome já existe). Pastas novas passam a ser conhecidas pela
+// IA nos próximos lotes. Em dry-run, nada é criado nem movido.
+func (s *reviewSession) Move(ctx context.Context, f *drive.FileInfo, folder, name string) (string, error) {
+	if s.dryRun {
+		slog.Info("dry-run: arquivo seria movido", "file", f.Name, "folder", folder, "name", name)
+		s.addFolder(folder)
+		return name, nil
+	}
+
+	dest, err := drive.FindOrCreateNestedFolder(ctx, s.backend, folder, s.backend.RootID())
+	if err != nil {
+		slog.Error("erro ao criar pasta destino", "folder", folder, "error", err)
+		return "", fmt.Errorf("erro ao criar pasta: %w", err)
+	}
+
+	oldParent := s.defaultParent
+	if len(f.Parents) > 0 {
+		oldParent = f.Parents[0]
+	}
+
+	var moved *drive.FileInfo
+	if name != f.Name {
+		moved, err = s.backend.MoveAndRenameFile(ctx, f.ID, name, dest.ID, oldParent)
+	} else {
+		moved, err = s.backend.MoveFile(ctx, f.ID, dest.ID, oldParent)
+	}
+	if err != nil {
+		slog.Error("erro ao mover arquivo", "file", f.Name, "error", err)
+		return "", fmt.Errorf("erro ao mover: %w", err)
+	}
+
+	s.addFolder(folder)
+	return moved.Name, nil
+}
+
+// addFolder registra folder entre as pastas conhecidas.
+func (s *reviewSession) addFolder(folder string) {
+	s.mu.Lock()
+	isNew := !slices.Contains(s.folders, folder)
+	if isNew {
+		s.folders = append(s.folders, folder)
+	}
+	s.mu.Unlock()
+	if isNew {
+		s.pf.AddFolder(folder)
+		slog.Debug("pasta adicionada ao histórico", "folder", folder)
+	}
+}
+
+// Folders são as pastas conhecidas: as da raiz e as criadas na sessão.
+func (s *reviewSession) Folders() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.folders)
+}
+
+// Cost descreve o custo da IA na sessão, ou "" se ainda não houver.
+func (s *reviewSession) Cost() string {
+	costs := s.cls.Costs()
+	if costs == nil || costs.Total() == 0 {
+		return ""
+	}
+	return formatCost(costs)
+}
+
+// RaiseBudget aumenta o orçamento esgotado em max_cost, retoma a
+// classificação e retorna o novo limite.
+func (s *reviewSession) RaiseBudget() string {
+	costs := s.cls.Costs()
+	costs.SetLimit(costs.Limit() + cfg.MaxCost)
+	s.pf.Resume()
+	return fmt.Sprintf("$%.2f", costs.Limit())
+}
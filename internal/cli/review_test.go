@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func TestReviewMoveReturnsBackendName(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "Financeiro"), 0755)
+	os.WriteFile(filepath.Join(root, "Financeiro", "boleto.pdf"), []byte("antigo"), 0644)
+	os.WriteFile(filepath.Join(root, "scan001.pdf"), []byte("novo"), 0644)
+
+	backend, err := drive.NewLocalBackend(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	f, err := backend.GetFile(ctx, "scan001.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &reviewSession{backend: backend, pf: new(classifier.Prefetcher), defaultParent: backend.RootID()}
+	name, err := s.Move(ctx, f, "Financeiro", "boleto.pdf")
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if name != "boleto (1).pdf" {
+		t.Errorf("Move = %q, want o nome dado pelo backend, boleto (1).pdf", name)
+	}
+	if _, err := os.Stat(filepath.Join(root, "Financeiro", name)); err != nil {
+		t.Errorf("arquivo fora do lugar: %v", err)
+	}
+}
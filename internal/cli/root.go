@@ -28,21 +28,51 @@ Fluxo:
   3. Pede confirmação antes de cada movimentação
   4. Reorganiza os arquivos em pastas organizadas`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return initConfig()
+			if err := initConfig(); err != nil {
+				return err
+			}
+			// O auth cria o perfil; os demais comandos exigem que ele exista
+			if cfg.Profile != "" && cmd.Name() != "auth" && !config.ProfileExists(cfg.Profile) {
+				return fmt.Errorf("perfil '%s' não existe; crie com: driver-organizer auth --profile %s", cfg.Profile, cfg.Profile)
+			}
+			return nil
 		},
 	}
 
 	// Flags globais
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "arquivo de configuração (padrão: ~/.config/driver-organizer/config.yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "perfil (conta) a usar, com token, API key, cache e backup próprios")
 	rootCmd.PersistentFlags().String("log-level", "info", "nível de log (debug, info, warn, error)")
 	rootCmd.PersistentFlags().Bool("dry-run", false, "simula operações sem mover arquivos")
+	rootCmd.PersistentFlags().String("backend", "drive", "onde organizar: drive (Google Drive) ou local (diretório)")
+	rootCmd.PersistentFlags().String("root", "", "diretório raiz quando --backend local")
+	rootCmd.PersistentFlags().String("shared-drive", "", "nome ou ID do drive compartilhado a usar em vez do Meu Drive")
+	rootCmd.PersistentFlags().Int("rate-limit", 10, "requisições por segundo à API do Drive (0 desativa)")
+	rootCmd.PersistentFlags().String("backup-folder", "backup", "pasta de backup")
 
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 	viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
 	viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	viper.BindPFlag("backend", rootCmd.PersistentFlags().Lookup("backend"))
+	viper.BindPFlag("local_root", rootCmd.PersistentFlags().Lookup("root"))
+	viper.BindPFlag("shared_drive", rootCmd.PersistentFlags().Lookup("shared-drive"))
+	viper.BindPFlag("rate_limit", rootCmd.PersistentFlags().Lookup("rate-limit"))
+	viper.BindPFlag("backup_folder", rootCmd.PersistentFlags().Lookup("backup-folder"))
 
 	// Subcomandos
-	rootCmd.AddCommand(newOrganizeCmd())
+	classifierFlags := newClassifierFlags()
+	rootCmd.AddCommand(newOrganizeCmd(classifierFlags))
+	rootCmd.AddCommand(newWatchCmd(classifierFlags))
 	rootCmd.AddCommand(newAuthCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newDuplicatesCmd())
+	rootCmd.AddCommand(newProfileCmd())
+	rootCmd.AddCommand(newRulesCmd())
+	rootCmd.AddCommand(newTreeCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newServeCmd())
 
 	return rootCmd
 }
@@ -62,18 +92,21 @@ func initConfig() error {
 	}
 
 	// Setup logging
-	level := slog.LevelInfo
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel()})
+	slog.SetDefault(slog.New(handler))
+
+	return nil
+}
+
+// logLevel converte o log_level da configuração.
+func logLevel() slog.Level {
 	switch cfg.LogLevel {
 	case "debug":
-		level = slog.LevelDebug
+		return slog.LevelDebug
 	case "warn":
-		level = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		level = slog.LevelError
+		return slog.LevelError
 	}
-
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
-	slog.SetDefault(slog.New(handler))
-
-	return nil
+	return slog.LevelInfo
 }
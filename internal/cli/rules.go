@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func newRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Regras declarativas da configuração",
+		Long: `As regras da seção "rules" do config.yaml são avaliadas antes da IA: arquivos
+que casam com uma regra vão direto para a pasta dela, sem custo e sempre do
+mesmo jeito.`,
+	}
+
+	test := &cobra.Command{
+		Use:   "test",
+		Short: "Avalia as regras contra os arquivos, sem mover nada",
+		Long: `Lista os arquivos soltos na raiz e pendentes no backup (ou os de --folder) e
+mostra, para cada um, a regra que casaria e o destino resultante. Nada é movido e
+a IA não é chamada.`,
+		Args: cobra.NoArgs,
+		RunE: runRulesTest,
+	}
+	test.Flags().String("folder", "", "avalia os arquivos desta pasta, recursivamente, em vez da raiz e do backup")
+	test.Flags().Bool("all", false, "mostra também os arquivos que nenhuma regra cobre")
+	cmd.AddCommand(test)
+
+	return cmd
+}
+
+func runRulesTest(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	folderPath, _ := cmd.Flags().GetString("folder")
+	showAll, _ := cmd.Flags().GetBool("all")
+
+	rules, err := compileRules()
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		fmt.Println("📏 Nenhuma regra configurada (seção \"rules\" do config.yaml).")
+		return nil
+	}
+	fmt.Printf("📏 %d regras carregadas\n", len(rules))
+
+	backend, err := openBackend(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	var files []located
+	if folderPath != "" {
+		folder, err := drive.FindNestedFolder(ctx, backend, folderPath, backend.RootID())
+		if err != nil {
+			return err
+		}
+		if folder == nil {
+			return fmt.Errorf("pasta '%s' não encontrada", folderPath)
+		}
+		fmt.Printf("📋 Listando arquivos em '%s'...\n", folderPath)
+		err = drive.WalkFiles(ctx, backend, folder.ID, folderPath, func(f *drive.FileInfo, p string) {
+			files = append(files, located{f, p})
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao listar arquivos: %w", err)
+		}
+	} else {
+		fmt.Println("📋 Listando arquivos na raiz e no backup...")
+		files, _, err = listUnorganized(ctx, backend)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("   Avaliando %d arquivos\n\n", len(files))
+
+	counts := make(map[string]int, len(rules))
+	matched := 0
+	for _, lf := range files {
+		meta := fileMetadata(lf.file)
+		meta.Folder = parentPath(lf.path)
+
+		s := rules.Match(meta)
+		if s == nil {
+			if showAll {
+				fmt.Printf("   ·  %s (nenhuma regra; iria para a IA)\n", lf.path)
+			}
+			continue
+		}
+
+		matched++
+		counts[s.Rule]++
+		fmt.Printf("   ✅ %s\n", lf.path)
+		fmt.Printf("      → %s  (regra '%s')\n", path.Join(s.SuggestedFolder, s.SuggestedName), s.Rule)
+	}
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("\n📊 Cobertos por regras: %d de %d arquivos\n", matched, len(files))
+	var unused []string
+	for _, r := range rules {
+		if n := counts[r.Name()]; n > 0 {
+			fmt.Printf("   %s: %d\n", r.Name(), n)
+		} else {
+			unused = append(unused, r.Name())
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Printf("   ⚠️  Regras que não casaram com nenhum arquivo: %v\n", unused)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/journal"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+	"github.com/vitoramaral10/driver-organizer/internal/server"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve [plan.json]",
+		Short: "Abre uma interface web para revisar um plano ou a fila do watch",
+		Long: `Serve uma página local que lista as entradas pendentes de um plano (por padrão,
+a fila do watch) com as sugestões da IA. Nela é possível aceitar, editar a pasta de
+destino ou o nome, pular e aplicar de uma vez as entradas aprovadas.
+
+Cada alteração é gravada no arquivo do plano na hora, então fechar o navegador ou
+o servidor não perde nada; o plano continua valendo para "organize apply". As
+movimentações ficam no diário e podem ser desfeitas com "undo".`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runServe,
+	}
+
+	cmd.Flags().String("listen", "127.0.0.1:8765", "endereço e porta do servidor")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	listen, _ := cmd.Flags().GetString("listen")
+	planPath := config.WatchQueuePath()
+	if len(args) > 0 {
+		planPath = args[0]
+	}
+
+	p, err := plan.Load(planPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("plano %s não encontrado; gere um com \"organize plan\" ou \"watch\"", planPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	dryRun := cfg.DryRun
+	if dryRun {
+		fmt.Println("🔍 MODO DRY-RUN: nenhum arquivo será movido")
+		fmt.Println()
+	}
+
+	backend, err := openBackendByLabel(ctx, p.Backend, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	var folders []string
+	rootFolders, err := backend.ListFolders(ctx, backend.RootID())
+	if err != nil {
+		slog.Warn("erro ao listar pastas existentes", "error", err)
+	}
+	for _, f := range rootFolders {
+		folders = append(folders, f.Name)
+	}
+
+	var sessionID string
+	if !dryRun {
+		j, err := journal.Open(config.JournalPath())
+		if err != nil {
+			return err
+		}
+		defer j.Close()
+
+		sessionID = journal.NewSessionID()
+		backend = journal.NewRecorder(backend, j, sessionID, p.Backend)
+	}
+
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %w", listen, err)
+	}
+	if host, _, _ := net.SplitHostPort(listen); host == "" || (host != "localhost" && !net.ParseIP(host).IsLoopback()) {
+		fmt.Println("⚠️  A interface não tem senha: qualquer um que alcance este endereço pode mover arquivos.")
+	}
+
+	srv := server.New(backend, planPath, folders, dryRun)
+	httpSrv := &http.Server{
+		Handler:           srv.Handler(),
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpSrv.Serve(ln) }()
+
+	fmt.Printf("🌐 Revisando %s (%d entradas)\n", planPath, len(p.Entries))
+	fmt.Printf("   Abra http://%s no navegador. Ctrl+C para encerrar.\n", ln.Addr())
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("erro no servidor: %w", err)
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelShutdown()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("erro ao encerrar servidor", "error", err)
+		}
+	}
+
+	fmt.Printf("\n👋 Servidor encerrado. ✅ Aplicados: %d\n", srv.Applied())
+	if sessionID != "" && srv.Applied() > 0 {
+		fmt.Printf("\n↩️  Para desfazer: driver-organizer undo --session %s\n", sessionID)
+	}
+	return nil
+}
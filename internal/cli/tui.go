@@ -0,0 +1,576 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/extract"
+)
+
+// reviewResult é o resultado da revisão.
+type reviewResult struct {
+	organized int
+	skipped   int
+	// deferred são os arquivos não revisados porque o orçamento acabou
+	deferred []*drive.FileInfo
+	// quit informa se o usuário encerrou a revisão antes do fim
+	quit bool
+}
+
+// runReviewTUI revisa files em tela cheia e retorna quando todos forem
+// revisados ou o usuário sair.
+func runReviewTUI(ctx context.Context, files []*drive.FileInfo, s *reviewSession) (reviewResult, error) {
+	m := &tuiModel{
+		ctx:    ctx,
+		s:      s,
+		files:  files,
+		status: make([]fileStatus, len(files)),
+		width:  100,
+		height: 30,
+	}
+
+	// Logs no stderr quebrariam a tela; ficam guardados e são mostrados ao sair
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: logLevel()})))
+	defer func() {
+		slog.SetDefault(prev)
+		os.Stderr.Write(logs.Bytes())
+	}()
+
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return m.result, fmt.Errorf("erro na interface: %w", err)
+	}
+	return m.result, nil
+}
+
+type fileStatus int
+
+const (
+	statusPending fileStatus = iota
+	statusOrganized
+	statusSkipped
+	statusFailed
+	statusDeferred
+)
+
+var statusIcon = map[fileStatus]string{
+	statusPending:   "  ",
+	statusOrganized: "✅",
+	statusSkipped:   "⏭ ",
+	statusFailed:    "❌",
+	statusDeferred:  "⏸ ",
+}
+
+type tuiMode int
+
+const (
+	modeLoading tuiMode = iota // esperando a sugestão do arquivo atual
+	modeReview                 // esperando uma ação
+	modeBusy                   // reclassificando ou movendo
+	modeText                   // digitando um texto
+	modePicker                 // escolhendo uma pasta
+	modeBudget                 // orçamento esgotado
+)
+
+type suggestionMsg struct {
+	s   *classifier.Suggestion
+	err error
+}
+
+type reclassifiedMsg struct {
+	title string
+	s     *classifier.Suggestion
+	err   error
+}
+
+type movedMsg struct {
+	folder, name string
+	err          error
+}
+
+type tuiModel struct {
+	ctx    context.Context
+	s      *reviewSession
+	files  []*drive.FileInfo
+	status []fileStatus
+	cur    int
+	result reviewResult
+
+	mode       tuiMode
+	suggestion *classifier.Suggestion
+	title      string
+	busy       string
+	message    string
+
+	// Campo de texto (modeText) e seletor de pastas (modePicker)
+	prompt   string
+	input    []rune
+	onSubmit func(text string) tea.Cmd
+	matches  []string
+	selected int
+
+	width, height int
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m *tuiModel) file() *drive.FileInfo {
+	return m.files[m.cur]
+}
+
+func (m *tuiModel) load() tea.Cmd {
+	m.mode = modeLoading
+	m.suggestion = nil
+	ctx, s, i := m.ctx, m.s, m.cur
+	return func() tea.Msg {
+		sug, err := s.Suggest(ctx, i)
+		return suggestionMsg{sug, err}
+	}
+}
+
+// next passa para o próximo arquivo ou encerra depois do último.
+func (m *tuiModel) next() tea.Cmd {
+	m.cur++
+	if m.cur >= len(m.files) {
+		return tea.Quit
+	}
+	return m.load()
+}
+
+// targetName é o nome sugerido para o arquivo atual, ou o nome dele.
+func (m *tuiModel) targetName() string {
+	if m.suggestion != nil && m.suggestion.SuggestedName != "" {
+		return m.suggestion.SuggestedName
+	}
+	return m.file().Name
+}
+
+func (m *tuiModel) move(folder, name string) tea.Cmd {
+	m.mode = modeBusy
+	m.busy = fmt.Sprintf("Movendo para %s...", folder)
+	ctx, s, f := m.ctx, m.s, m.file()
+	return func() tea.Msg {
+		name, err := s.Move(ctx, f, folder, name)
+		return movedMsg{folder, name, err}
+	}
+}
+
+func (m *tuiModel) reclassify(busy, title string, fn func(ctx context.Context) (*classifier.Suggestion, error)) tea.Cmd {
+	m.mode = modeBusy
+	m.busy = busy
+	ctx := m.ctx
+	return func() tea.Msg {
+		s, err := fn(ctx)
+		return reclassifiedMsg{title, s, err}
+	}
+}
+
+func (m *tuiModel) content() tea.Cmd {
+	s, f := m.s, m.file()
+	return m.reclassify("🔎 Analisando o conteúdo do arquivo...", "Sugestão pelo conteúdo", func(ctx context.Context) (*classifier.Suggestion, error) {
+		return s.Content(ctx, f)
+	})
+}
+
+func (m *tuiModel) quit() tea.Cmd {
+	m.result.quit = true
+	return tea.Quit
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case suggestionMsg:
+		return m, m.onSuggestion(msg)
+
+	case reclassifiedMsg:
+		m.mode = modeReview
+		if errors.Is(msg.err, extract.ErrScanned) {
+			m.message = "📷 PDF digitalizado, sem camada de texto: o conteúdo não pode ser lido"
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = fmt.Sprintf("⚠️  %v; mantendo a sugestão atual", msg.err)
+			return m, nil
+		}
+		m.suggestion, m.title = msg.s, msg.title
+		m.message = ""
+		return m, nil
+
+	case movedMsg:
+		if msg.err != nil {
+			m.status[m.cur] = statusFailed
+			m.result.skipped++
+			m.message = fmt.Sprintf("❌ %s: %v", m.file().Name, msg.err)
+			return m, m.next()
+		}
+		m.status[m.cur] = statusOrganized
+		m.result.organized++
+		m.message = fmt.Sprintf("✅ %s → %s", m.file().Name, msg.folder)
+		if m.s.dryRun {
+			m.message = fmt.Sprintf("[DRY-RUN] Moveria: %s → %s", m.file().Name, msg.folder)
+		}
+		if msg.name != m.file().Name {
+			m.message += "/" + msg.name
+		}
+		return m, m.next()
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, m.quit()
+		}
+		switch m.mode {
+		case modeReview:
+			return m, m.reviewKey(msg)
+		case modeText:
+			return m, m.textKey(msg)
+		case modePicker:
+			return m, m.pickerKey(msg)
+		case modeBudget:
+			return m, m.budgetKey(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m *tuiModel) onSuggestion(msg suggestionMsg) tea.Cmd {
+	if errors.Is(msg.err, classifier.ErrBudgetExceeded) {
+		m.mode = modeBudget
+		return nil
+	}
+	if msg.err != nil {
+		m.status[m.cur] = statusFailed
+		m.result.skipped++
+		m.message = fmt.Sprintf("❌ Erro ao classificar %s: %v", m.file().Name, msg.err)
+		return m.next()
+	}
+
+	m.suggestion = msg.s
+	m.title = "Sugestão da IA"
+	if msg.s.Rule != "" {
+		m.title = "Regra da configuração"
+	}
+	m.mode = modeReview
+	if msg.s.NeedsContent {
+		return m.content()
+	}
+	return nil
+}
+
+func (m *tuiModel) reviewKey(msg tea.KeyMsg) tea.Cmd {
+	f := m.file()
+	switch msg.String() {
+	case "m", "enter":
+		if m.suggestion.SuggestedFolder == "" {
+			m.message = "⚠️  Sem pasta sugerida; escolha uma com (r) ou (c)"
+			return nil
+		}
+		return m.move(m.suggestion.SuggestedFolder, m.targetName())
+
+	case "d":
+		m.openText("Descreva o arquivo (ex: relatório mensal de vendas)", "", func(text string) tea.Cmd {
+			if text == "" {
+				m.message = "Descrição vazia, mantendo sugestão atual."
+				return nil
+			}
+			s := m.s
+			return m.reclassify("🤖 Reanalisando com sua descrição...", "Nova sugestão", func(ctx context.Context) (*classifier.Suggestion, error) {
+				return s.Describe(ctx, f, text)
+			})
+		})
+
+	case "v":
+		return m.content()
+
+	case "r":
+		m.openPicker("Pasta de destino", m.suggestion.SuggestedFolder)
+
+	case "c":
+		m.openPicker("Nova pasta", "")
+
+	case "n":
+		name := m.targetName()
+		m.openText("Novo nome do arquivo", name, func(text string) tea.Cmd {
+			if text == "" {
+				text = name
+			}
+			return m.move(m.suggestion.SuggestedFolder, text)
+		})
+
+	case "p":
+		m.status[m.cur] = statusSkipped
+		m.result.skipped++
+		m.message = "⏭️  Pulado: " + f.Name
+		return m.next()
+
+	case "q", "esc":
+		return m.quit()
+	}
+	return nil
+}
+
+func (m *tuiModel) budgetKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "s", "y":
+		m.message = "💰 Novo orçamento: " + m.s.RaiseBudget()
+		return m.load()
+	case "n", "q", "esc":
+		for i := m.cur; i < len(m.files); i++ {
+			m.status[i] = statusDeferred
+		}
+		m.result.deferred = append(m.result.deferred, m.files[m.cur:]...)
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *tuiModel) openText(prompt, initial string, onSubmit func(text string) tea.Cmd) {
+	m.mode = modeText
+	m.prompt = prompt
+	m.input = []rune(initial)
+	m.onSubmit = onSubmit
+}
+
+// editInput aplica ao campo de texto as teclas de edição. Retorna false se a
+// tecla não é de edição.
+func (m *tuiModel) editInput(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes:
+		m.input = append(m.input, msg.Runes...)
+	case tea.KeySpace:
+		m.input = append(m.input, ' ')
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyCtrlU:
+		m.input = nil
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *tuiModel) textKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.mode = modeReview
+		return m.onSubmit(strings.TrimSpace(string(m.input)))
+	case tea.KeyEsc:
+		m.mode = modeReview
+		return nil
+	}
+	m.editInput(msg)
+	return nil
+}
+
+func (m *tuiModel) openPicker(prompt, initial string) {
+	m.mode = modePicker
+	m.prompt = prompt
+	m.input = []rune(initial)
+	m.filter()
+}
+
+// filter atualiza as opções do seletor: o texto digitado, se ainda não for
+// uma pasta conhecida, seguido das pastas que casam com ele.
+func (m *tuiModel) filter() {
+	query := strings.Trim(strings.TrimSpace(string(m.input)), "/")
+	folders := m.s.Folders()
+	if s := m.suggestion; s != nil && s.SuggestedFolder != "" && !slices.Contains(folders, s.SuggestedFolder) {
+		folders = append(folders, s.SuggestedFolder)
+	}
+
+	m.matches = fuzzyFilter(query, folders)
+	if query != "" && !slices.Contains(folders, query) {
+		m.matches = append([]string{query}, m.matches...)
+	}
+	m.selected = 0
+}
+
+func (m *tuiModel) pickerKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if len(m.matches) == 0 {
+			return nil
+		}
+		return m.move(m.matches[m.selected], m.targetName())
+	case tea.KeyEsc:
+		m.mode = modeReview
+		return nil
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.selected > 0 {
+			m.selected--
+		}
+		return nil
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		if m.selected < len(m.matches)-1 {
+			m.selected++
+		}
+		return nil
+	}
+	if m.editInput(msg) {
+		m.filter()
+	}
+	return nil
+}
+
+var (
+	paneStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1)
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	dimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	barStyle    = lipgloss.NewStyle().Reverse(true)
+)
+
+func (m *tuiModel) View() string {
+	if m.cur >= len(m.files) {
+		return ""
+	}
+
+	// Duas linhas de status e ajuda, mais as bordas dos painéis
+	paneHeight := max(m.height-4, 5)
+	queueWidth := min(max(m.width/3, 20), 48)
+	detailWidth := max(m.width-queueWidth-4, 30)
+
+	queue := paneStyle.Width(queueWidth).Height(paneHeight).Render(m.queueView(queueWidth-2, paneHeight))
+	detail := paneStyle.Width(detailWidth).Height(paneHeight).Render(m.detailView(detailWidth - 2))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, queue, detail),
+		m.statusBar(),
+		dimStyle.Render(m.help()),
+	)
+}
+
+func (m *tuiModel) queueView(width, height int) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Fila %d/%d", m.cur+1, len(m.files))))
+	b.WriteString("\n")
+
+	// Janela da fila com o arquivo atual perto do topo
+	rows := height - 1
+	start := max(0, min(m.cur-rows/4, len(m.files)-rows))
+	for i := start; i < len(m.files) && i < start+rows; i++ {
+		line := truncate(m.files[i].Name, width-3)
+		if i == m.cur {
+			b.WriteString(accentStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(statusIcon[m.status[i]] + " " + line)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (m *tuiModel) detailView(width int) string {
+	f := m.file()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", titleStyle.Render("📄 "+truncate(f.Name, width-3)))
+	fmt.Fprintf(&b, "Tipo: %s\n", f.MimeType)
+	fmt.Fprintf(&b, "Tamanho: %s\n", formatSize(f.Size))
+	fmt.Fprintf(&b, "Criado: %s\n", f.CreatedTime)
+	fmt.Fprintf(&b, "Modificado: %s\n\n", f.ModifiedTime)
+
+	switch {
+	case m.mode == modeLoading:
+		b.WriteString(dimStyle.Render("🤖 Classificando..."))
+	case m.mode == modeBudget:
+		b.WriteString(accentStyle.Render("💰 Orçamento da IA atingido (" + m.s.Cost() + ")"))
+		b.WriteString("\n\nAumentar o orçamento e continuar? (s/n)\nCom n, os arquivos restantes ficam no backup.")
+	case m.suggestion != nil:
+		s := m.suggestion
+		fmt.Fprintf(&b, "%s\n", accentStyle.Render("🤖 "+m.title))
+		fmt.Fprintf(&b, "Pasta: %s\n", s.SuggestedFolder)
+		if s.SuggestedName != "" && s.SuggestedName != f.Name {
+			fmt.Fprintf(&b, "Nome: %s\n", s.SuggestedName)
+		}
+		fmt.Fprintf(&b, "Motivo: %s\n", lipgloss.NewStyle().Width(width).Render(s.Reason))
+		fmt.Fprintf(&b, "Confiança: %.0f%%\n", s.Confidence*100)
+	}
+
+	switch m.mode {
+	case modeBusy:
+		fmt.Fprintf(&b, "\n%s", dimStyle.Render(m.busy))
+	case modeText:
+		fmt.Fprintf(&b, "\n%s\n> %s█", titleStyle.Render(m.prompt), string(m.input))
+	case modePicker:
+		fmt.Fprintf(&b, "\n%s\n> %s█\n", titleStyle.Render(m.prompt), string(m.input))
+		for i, folder := range m.matches {
+			if i >= 8 {
+				fmt.Fprintf(&b, dimStyle.Render("  ... e mais %d")+"\n", len(m.matches)-i)
+				break
+			}
+			label := truncate(folder, width-4)
+			if i == 0 && !slices.Contains(m.s.Folders(), folder) && folder != m.suggestion.SuggestedFolder {
+				label += dimStyle.Render(" (nova)")
+			}
+			if i == m.selected {
+				b.WriteString(accentStyle.Render("▶ "+label) + "\n")
+			} else {
+				b.WriteString("  " + label + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func (m *tuiModel) statusBar() string {
+	bar := fmt.Sprintf(" ✅ %d organizados  ⏭️  %d pulados", m.result.organized, m.result.skipped)
+	if cost := m.s.Cost(); cost != "" {
+		bar += "  💰 " + cost
+	}
+	if m.message != "" {
+		bar += "  │ " + m.message
+	}
+	bar = truncate(bar, m.width)
+	if pad := m.width - lipgloss.Width(bar); pad > 0 {
+		bar += strings.Repeat(" ", pad)
+	}
+	return barStyle.Render(bar)
+}
+
+func (m *tuiModel) help() string {
+	switch m.mode {
+	case modeReview:
+		return " m/enter mover · d descrever · v ver conteúdo · r pasta · n nome · c nova pasta · p pular · q sair"
+	case modeText:
+		return " enter confirmar · esc cancelar · ctrl+u limpar"
+	case modePicker:
+		return " digite para filtrar · ↑/↓ escolher · enter mover · esc cancelar"
+	case modeBudget:
+		return " s aumentar orçamento · n parar aqui"
+	}
+	return " ctrl+c sair"
+}
+
+// truncate corta s em width colunas, terminando com "…".
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r)) > width-1 {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
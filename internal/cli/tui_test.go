@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func newTestTUI(m *drive.MemoryBackend, files []*drive.FileInfo, folders ...string) *tuiModel {
+	s := &reviewSession{
+		backend:       m,
+		pf:            new(classifier.Prefetcher),
+		defaultParent: m.RootID(),
+		folders:       folders,
+	}
+	return &tuiModel{
+		ctx:    context.Background(),
+		s:      s,
+		files:  files,
+		status: make([]fileStatus, len(files)),
+		width:  100,
+		height: 30,
+	}
+}
+
+// send entrega msg ao modelo e, como faria o programa do bubbletea, executa
+// as operações que ele disparar (mover ou reclassificar). Pedidos de sugestão
+// não são executados: o teste responde com suggestionMsg.
+func send(t *testing.T, m *tuiModel, msg tea.Msg) {
+	t.Helper()
+	_, cmd := m.Update(msg)
+	for cmd != nil && m.mode == modeBusy {
+		_, cmd = m.Update(cmd())
+	}
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestTUIReviewFlow(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	boleto := m.AddFile("boleto.pdf", "application/pdf", 100)
+	photo := m.AddFile("IMG_0001.jpg", "image/jpeg", 100)
+	notes := m.AddFile("notas.txt", "text/plain", 10)
+	tui := newTestTUI(m, []*drive.FileInfo{boleto, photo, notes}, "Financeiro/Boletos", "Fotos", "Documentos")
+
+	// Aceita a sugestão, com o nome sugerido
+	send(t, tui, suggestionMsg{s: &classifier.Suggestion{SuggestedFolder: "Financeiro/Boletos", SuggestedName: "Boleto Luz.pdf"}})
+	send(t, tui, key("m"))
+	assertIn(t, m, boleto.ID, "Financeiro/Boletos")
+	if f, _ := m.GetFile(context.Background(), boleto.ID); f.Name != "Boleto Luz.pdf" {
+		t.Errorf("nome = %s, want Boleto Luz.pdf", f.Name)
+	}
+
+	// Escolhe outra pasta no seletor, filtrando por "fot"
+	send(t, tui, suggestionMsg{s: &classifier.Suggestion{SuggestedFolder: "Documentos"}})
+	send(t, tui, key("r"))
+	tui.input = nil
+	send(t, tui, key("fot"))
+	// O texto digitado vem primeiro, seguido da melhor pasta
+	if len(tui.matches) < 2 || tui.matches[0] != "fot" || tui.matches[1] != "Fotos" {
+		t.Fatalf("opções do seletor = %v, want fot e Fotos primeiro", tui.matches)
+	}
+	send(t, tui, key("down"))
+	send(t, tui, key("enter"))
+	assertIn(t, m, photo.ID, "Fotos")
+
+	// Pula o último e encerra
+	send(t, tui, suggestionMsg{s: &classifier.Suggestion{SuggestedFolder: "Documentos"}})
+	send(t, tui, key("p"))
+	assertIn(t, m, notes.ID, "")
+
+	want := []fileStatus{statusOrganized, statusOrganized, statusSkipped}
+	if !slices.Equal(tui.status, want) || tui.result.organized != 2 || tui.result.skipped != 1 {
+		t.Errorf("status = %v, resultado = %+v", tui.status, tui.result)
+	}
+}
+
+func TestTUIBudgetDefersRemaining(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	files := []*drive.FileInfo{m.AddFile("a.pdf", "application/pdf", 1), m.AddFile("b.pdf", "application/pdf", 1)}
+	tui := newTestTUI(m, files)
+
+	send(t, tui, suggestionMsg{err: classifier.ErrBudgetExceeded})
+	if tui.mode != modeBudget {
+		t.Fatalf("modo = %d, want modeBudget", tui.mode)
+	}
+	send(t, tui, key("n"))
+
+	if len(tui.result.deferred) != 2 || tui.status[1] != statusDeferred {
+		t.Errorf("adiados = %d, status = %v", len(tui.result.deferred), tui.status)
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	folders := []string{"Documentos/Contratos", "Fotos", "Financeiro/Faturas", "Trabalho"}
+	tests := []struct {
+		query string
+		want  string // primeira opção
+	}{
+		{"fot", "Fotos"},
+		{"fat", "Financeiro/Faturas"},
+		{"cont", "Documentos/Contratos"},
+		{"TRAB", "Trabalho"},
+	}
+	for _, tt := range tests {
+		got := fuzzyFilter(tt.query, folders)
+		if len(got) == 0 || got[0] != tt.want {
+			t.Errorf("fuzzyFilter(%q) = %v, want %s primeiro", tt.query, got, tt.want)
+		}
+	}
+	if got := fuzzyFilter("xyz", folders); len(got) != 0 {
+		t.Errorf("fuzzyFilter(xyz) = %v, want nenhuma", got)
+	}
+	if got := fuzzyFilter("", folders); len(got) != len(folders) {
+		t.Errorf("fuzzyFilter vazio = %v, want todas", got)
+	}
+}
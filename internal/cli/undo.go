@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/journal"
+)
+
+func newUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Desfaz uma sessão de organização",
+		Long: `Reverte as movimentações e renomeações de uma sessão do organize, na
+ordem inversa, usando o diário de operações. Pastas criadas na sessão vão para a
+lixeira se ficarem vazias. Arquivos alterados depois da sessão são pulados.
+
+Sem --session, desfaz a última sessão ainda não desfeita.`,
+		RunE: runUndo,
+	}
+
+	cmd.Flags().String("session", "", "ID da sessão a desfazer (padrão: a última)")
+	cmd.Flags().Bool("list", false, "lista as sessões registradas no diário")
+	cmd.Flags().BoolP("yes", "y", false, "não pede confirmação")
+
+	return cmd
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	sessionID, _ := cmd.Flags().GetString("session")
+	list, _ := cmd.Flags().GetBool("list")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	entries, err := journal.ReadAll(config.JournalPath())
+	if err != nil {
+		return err
+	}
+	sessions := journal.Sessions(entries)
+
+	if list {
+		if len(sessions) == 0 {
+			fmt.Println("📝 Nenhuma sessão registrada no diário.")
+			return nil
+		}
+		fmt.Println("📝 Sessões registradas:")
+		for _, s := range sessions {
+			status := ""
+			if s.Undone {
+				status = " (desfeita)"
+			}
+			fmt.Printf("   %s  %s  %d operações  [%s]%s\n",
+				s.ID, s.Start.Local().Format("02/01/2006 15:04"), s.Operations, s.Backend, status)
+		}
+		return nil
+	}
+
+	var session *journal.Session
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		if (sessionID == "" && !s.Undone) || s.ID == sessionID {
+			session = &s
+			break
+		}
+	}
+	if session == nil {
+		if sessionID != "" {
+			return fmt.Errorf("sessão '%s' não encontrada no diário", sessionID)
+		}
+		fmt.Println("📝 Nenhuma sessão para desfazer.")
+		return nil
+	}
+	if session.Undone {
+		fmt.Printf("⚠️  A sessão %s já foi desfeita; operações já revertidas serão puladas.\n", session.ID)
+	}
+
+	fmt.Printf("↩️  Sessão %s: %d operações [%s]\n", session.ID, session.Operations, session.Backend)
+
+	dryRun := cfg.DryRun
+	if dryRun {
+		fmt.Println("🔍 MODO DRY-RUN: nenhum arquivo será movido")
+	} else if !yes {
+		fmt.Print("Desfazer esta sessão? [s/N]: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "s" && answer != "sim" {
+			fmt.Println("❌ Cancelado.")
+			return nil
+		}
+	}
+	fmt.Println()
+
+	backend, err := openBackendByLabel(ctx, session.Backend, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	result := journal.Undo(ctx, backend, journal.SessionEntries(entries, session.ID), dryRun)
+
+	if !dryRun && len(result.Errors) == 0 {
+		j, err := journal.Open(config.JournalPath())
+		if err != nil {
+			return err
+		}
+		defer j.Close()
+		if err := j.Append(journal.Entry{SessionID: session.ID, Op: journal.OpUndo, Backend: session.Backend}); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n✅ Undo concluído!\n")
+	fmt.Printf("   ↩️  Revertidos: %d\n", result.Reverted)
+	fmt.Printf("   🗑️  Pastas removidas: %d\n", result.FoldersTrashed)
+	fmt.Printf("   ⏭️  Pulados: %d\n", result.Skipped)
+	if len(result.Errors) > 0 {
+		fmt.Printf("   ❌ Erros: %d (execute o undo novamente para tentar de novo)\n", len(result.Errors))
+		return fmt.Errorf("%d operações não puderam ser desfeitas", len(result.Errors))
+	}
+	return nil
+}
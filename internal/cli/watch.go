@@ -0,0 +1,450 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/extract"
+	"github.com/vitoramaral10/driver-organizer/internal/journal"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+	"github.com/vitoramaral10/driver-organizer/internal/watch"
+)
+
+func newWatchCmd(classifierFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Observa o Drive e organiza os arquivos novos",
+		Long: `Consulta periodicamente a API de mudanças do Drive e classifica os arquivos
+adicionados à raiz (ou à pasta de entrada, com --inbox). Com --auto, as sugestões
+com confiança mínima são aplicadas; as demais vão para uma fila de revisão no
+formato de "organize plan", aplicada com "organize apply".
+
+O token da API de mudanças é salvo a cada página processada, então o watch
+continua de onde parou depois de reiniciado. Na primeira execução só as
+alterações feitas a partir dali são consideradas; use "organize" para o que já
+está na raiz.`,
+		Args: cobra.NoArgs,
+		RunE: runWatch,
+	}
+
+	cmd.Flags().AddFlagSet(classifierFlags)
+	cmd.Flags().String("inbox", "", "pasta de entrada a observar em vez da raiz (ex: \"Entrada\")")
+	cmd.Flags().Duration("interval", time.Minute, "intervalo entre consultas")
+	cmd.Flags().Bool("auto", false, "aplica as sugestões com confiança mínima; o resto vai para a fila")
+	cmd.Flags().String("queue", "", "arquivo da fila de revisão (padrão: watch_queue.json no diretório de config)")
+	cmd.Flags().Bool("once", false, "processa as alterações pendentes e sai")
+
+	viper.BindPFlag("watch_inbox", cmd.Flags().Lookup("inbox"))
+	viper.BindPFlag("watch_interval", cmd.Flags().Lookup("interval"))
+	viper.BindPFlag("watch_auto", cmd.Flags().Lookup("auto"))
+
+	return cmd
+}
+
+// watcher mantém o estado de uma sessão do watch entre as consultas.
+type watcher struct {
+	backend    drive.Backend
+	cls        *classifier.Classifier
+	cache      *classifier.Cache
+	fetcher    *contentFetcher
+	folders    []string
+	inboxPath  string
+	queue      *plan.Plan
+	queuePath  string
+	dryRun     bool
+	budgetSeen bool
+
+	// Se um lote falha no meio (ex: a fila está travada), a página volta na
+	// próxima consulta. As alterações da fila ainda não gravadas ficam em
+	// pendingRemoved e pendingQueued, e os arquivos já aplicados ou postos na
+	// fila ficam em handled (ID → modifiedTime), para não serem tratados de
+	// novo.
+	pendingRemoved []string
+	pendingQueued  []plan.Entry
+	handled        map[string]string
+
+	applied, queued int
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	once, _ := cmd.Flags().GetBool("once")
+	queuePath, _ := cmd.Flags().GetString("queue")
+	if queuePath == "" {
+		queuePath = config.WatchQueuePath()
+	}
+
+	dryRun := cfg.DryRun
+	if dryRun {
+		fmt.Println("🔍 MODO DRY-RUN: nenhum arquivo será movido e o progresso não é salvo")
+		fmt.Println()
+	}
+	if cfg.MinConfidence < 0 || cfg.MinConfidence > 1 {
+		return fmt.Errorf("--min-confidence deve estar entre 0 e 1")
+	}
+	if cfg.WatchInterval < 10*time.Second {
+		return fmt.Errorf("--interval deve ser de pelo menos 10s")
+	}
+
+	if err := prepareProvider(); err != nil {
+		return err
+	}
+
+	backend, err := openBackend(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	src, ok := backend.(drive.ChangeSource)
+	if !ok {
+		return fmt.Errorf("watch usa a API de mudanças do Google Drive; não disponível com --backend %s", cfg.Backend)
+	}
+	label := backendLabel(backend)
+
+	// Pasta observada; o alias "root" precisa virar o ID real, que é o que
+	// aparece nos parents das alterações
+	var watched *drive.FileInfo
+	if cfg.WatchInbox != "" {
+		watched, err = drive.FindNestedFolder(ctx, backend, cfg.WatchInbox, backend.RootID())
+		if err != nil {
+			return err
+		}
+		if watched == nil {
+			return fmt.Errorf("pasta de entrada '%s' não encontrada", cfg.WatchInbox)
+		}
+	} else {
+		watched, err = backend.GetFile(ctx, backend.RootID())
+		if err != nil {
+			return fmt.Errorf("erro ao obter pasta raiz: %w", err)
+		}
+	}
+
+	state, err := watch.LoadState(config.WatchStatePath())
+	if err != nil {
+		return err
+	}
+	state.ReadOnly = dryRun
+	started, err := watch.Init(ctx, src, state, label)
+	if err != nil {
+		return err
+	}
+	if started {
+		fmt.Println("👀 Primeira execução: só arquivos adicionados a partir de agora serão processados.")
+		fmt.Println("   Para o que já está na raiz, use: driver-organizer organize")
+	}
+
+	queue, err := loadWatchQueue(queuePath, label)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		j, err := journal.Open(config.JournalPath())
+		if err != nil {
+			return err
+		}
+		defer j.Close()
+
+		sessionID := journal.NewSessionID()
+		backend = journal.NewRecorder(backend, j, sessionID, label)
+		fmt.Printf("📝 Sessão %s (desfaça com: driver-organizer undo --session %s)\n", sessionID, sessionID)
+	}
+
+	fmt.Println("🤖 Inicializando classificador IA...")
+	cls, err := openClassifier(ctx)
+	if err != nil {
+		return err
+	}
+	defer cls.Close()
+	if queue.Model == "" {
+		queue.Model = cls.ModelName()
+	}
+
+	cache, err := classifier.OpenCache(config.CachePath(), cls.ModelName())
+	if err != nil {
+		slog.Warn("cache persistente indisponível, usando cache em memória", "error", err)
+		cache = classifier.NewCache()
+	}
+	defer cache.Close()
+
+	w := &watcher{
+		backend:   backend,
+		cls:       cls,
+		cache:     cache,
+		fetcher:   newContentFetcher(backend),
+		inboxPath: cfg.WatchInbox,
+		queue:     queue,
+		queuePath: queuePath,
+		dryRun:    dryRun,
+	}
+
+	where := "raiz"
+	if cfg.WatchInbox != "" {
+		where = "'" + cfg.WatchInbox + "'"
+	}
+	if cfg.WatchAuto {
+		fmt.Printf("\n👀 Observando %s a cada %s; aplicando sugestões com confiança ≥ %.0f%%\n",
+			where, cfg.WatchInterval, cfg.MinConfidence*100)
+	} else {
+		fmt.Printf("\n👀 Observando %s a cada %s; sugestões vão para a fila %s\n", where, cfg.WatchInterval, queuePath)
+	}
+	if !once {
+		fmt.Println("   Ctrl+C para sair.")
+	}
+
+	for {
+		if err := w.refreshFolders(ctx); err != nil {
+			slog.Warn("erro ao listar pastas existentes", "error", err)
+		}
+
+		n, err := watch.Poll(ctx, src, state, label, watched.ID, w.tracked, w.handle)
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			// Erros de rede ou da API não encerram o watch; a próxima
+			// consulta recomeça do último token salvo
+			slog.Error("erro ao consultar mudanças", "error", err)
+			fmt.Printf("   ❌ Erro ao consultar mudanças: %v\n", err)
+		case n > 0:
+			fmt.Printf("   %s: %d arquivos processados\n", time.Now().Format("15:04:05"), n)
+		}
+
+		if once || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(cfg.WatchInterval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("\n👋 Watch encerrado.\n")
+	fmt.Printf("   ✅ Aplicados: %d\n", w.applied)
+	fmt.Printf("   📥 Na fila: %d\n", w.queued)
+	if len(w.queue.Entries) > 0 && !dryRun {
+		fmt.Printf("\n   Revise a fila (%d entradas) e aplique com: driver-organizer organize apply %s\n",
+			len(w.queue.Entries), queuePath)
+	}
+	printCostSummary(cls.Costs())
+	return nil
+}
+
+// loadWatchQueue abre a fila de revisão em path ou cria uma vazia. A fila é um
+// plano, então precisa ser do mesmo backend.
+func loadWatchQueue(path, label string) (*plan.Plan, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return &plan.Plan{
+			Version:   plan.Version,
+			CreatedAt: time.Now().UTC(),
+			Backend:   label,
+		}, nil
+	}
+
+	q, err := plan.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if q.Backend != label {
+		return nil, fmt.Errorf("a fila %s é de outro backend (%s); use --queue para outra fila", path, q.Backend)
+	}
+	return q, nil
+}
+
+// refreshFolders atualiza as pastas da raiz que a IA conhece.
+func (w *watcher) refreshFolders(ctx context.Context) error {
+	folders, err := w.backend.ListFolders(ctx, w.backend.RootID())
+	if err != nil {
+		return err
+	}
+	w.folders = w.folders[:0]
+	for _, f := range folders {
+		w.folders = append(w.folders, f.Name)
+	}
+	return nil
+}
+
+// handle classifica os arquivos de uma página de alterações e aplica ou põe
+// na fila cada um. Arquivos que não puderam ser classificados também vão para
+// a fila, sem destino, para que nenhuma alteração se perca.
+func (w *watcher) handle(ctx context.Context, b watch.Batch) error {
+	// As alterações na fila são gravadas só no fim do lote, sobre a fila relida
+	// do disco
+	w.pendingRemoved = append(w.pendingRemoved, b.Left...)
+	if w.handled == nil {
+		w.handled = make(map[string]string)
+	}
+
+	// Arquivos já tratados em uma tentativa anterior deste lote ficam de fora
+	files := slices.DeleteFunc(slices.Clone(b.Files), func(f *drive.FileInfo) bool {
+		modified, ok := w.handled[f.ID]
+		return ok && modified == f.ModifiedTime
+	})
+
+	folderOf := make(map[string]string, len(files))
+	for _, f := range files {
+		folderOf[f.ID] = w.inboxPath
+	}
+	// O Prefetcher só vale para este lote; sem o cancel, a goroutine dele
+	// ficaria esperando um Resume quando o orçamento se esgota
+	pfCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pf := classifier.NewPrefetcher(pfCtx, w.cls, w.cache, prefetchItems(files, folderOf), w.folders, cfg.BatchSize, len(files))
+	for i, f := range files {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		filePath := path.Join(w.inboxPath, f.Name)
+		e := plan.NewEntry(f, filePath)
+
+		suggestion, err := pf.Get(ctx, i)
+		switch {
+		case errors.Is(err, classifier.ErrBudgetExceeded):
+			if !w.budgetSeen {
+				fmt.Printf("   💰 Orçamento de $%.2f atingido: os próximos arquivos vão para a fila sem sugestão.\n", w.cls.Costs().Limit())
+				w.budgetSeen = true
+			}
+			e.Reason = "orçamento de IA atingido"
+		case err != nil:
+			slog.Error("erro na classificação", "file", f.Name, "error", err)
+			e.Reason = fmt.Sprintf("erro ao classificar: %v", err)
+		default:
+			if suggestion.NeedsContent {
+				suggestion = w.classifyWithContent(ctx, f, suggestion)
+			}
+			e.TargetFolder = suggestion.SuggestedFolder
+			e.TargetName = suggestion.SuggestedName
+			e.Reason = suggestion.Reason
+			e.Confidence = suggestion.Confidence
+		}
+
+		confident := e.TargetFolder != "" && e.Confidence >= cfg.MinConfidence
+		if cfg.WatchAuto && confident {
+			err := e.Apply(ctx, w.backend, w.dryRun)
+			switch {
+			case errors.Is(err, plan.ErrStale):
+				fmt.Printf("   ⚠️  %s: %v, pulando\n", filePath, err)
+				w.handled[f.ID] = f.ModifiedTime
+				continue
+			case err != nil:
+				slog.Error("falha ao aplicar sugestão", "file", filePath, "error", err)
+				fmt.Printf("   ❌ %s: %v; vai para a fila\n", filePath, err)
+			case w.dryRun:
+				fmt.Printf("   [DRY-RUN] Moveria: %s → %s\n", filePath, e.TargetPath())
+				w.handled[f.ID] = f.ModifiedTime
+				w.applied++
+				continue
+			default:
+				fmt.Printf("   ✅ %s → %s\n", filePath, e.TargetPath())
+				if !slices.Contains(w.folders, e.TargetFolder) {
+					w.folders = append(w.folders, e.TargetFolder)
+				}
+				w.pendingRemoved = append(w.pendingRemoved, f.ID)
+				w.handled[f.ID] = f.ModifiedTime
+				w.applied++
+				continue
+			}
+		}
+
+		// Na fila, as sugestões confiáveis já vêm aprovadas
+		e.Approved = confident
+		if w.dryRun {
+			fmt.Printf("   [DRY-RUN] Poria na fila: %s → %s (%.0f%%)\n", filePath, e.TargetPath(), e.Confidence*100)
+		} else {
+			fmt.Printf("   📥 %s → %s (%.0f%%), na fila\n", filePath, e.TargetPath(), e.Confidence*100)
+		}
+		w.pendingQueued = append(w.pendingQueued, e)
+		w.handled[f.ID] = f.ModifiedTime
+		w.queued++
+	}
+
+	if err := w.updateQueue(w.pendingRemoved, w.pendingQueued); err != nil {
+		return err
+	}
+	w.pendingRemoved, w.pendingQueued, w.handled = nil, nil, nil
+	return nil
+}
+
+// tracked informa se o arquivo fileID está na fila, gravada ou pendente. Só
+// esses arquivos interessam quando saem da pasta observada.
+func (w *watcher) tracked(fileID string) bool {
+	isFile := func(e plan.Entry) bool { return e.FileID == fileID }
+	return slices.ContainsFunc(w.queue.Entries, isFile) || slices.ContainsFunc(w.pendingQueued, isFile)
+}
+
+// updateQueue tira da fila os arquivos em removed e põe os de queued. Fora do
+// dry-run, a fila é relida e gravada com o plano travado, para não desfazer o
+// que o "serve" alterou nela enquanto o lote era classificado.
+func (w *watcher) updateQueue(removed []string, queued []plan.Entry) error {
+	if len(removed) == 0 && len(queued) == 0 {
+		return nil
+	}
+
+	q := w.queue
+	if !w.dryRun {
+		unlock, err := plan.Lock(w.queuePath)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if q, err = loadWatchQueue(w.queuePath, w.queue.Backend); err != nil {
+			return err
+		}
+		if q.Model == "" {
+			q.Model = w.queue.Model
+		}
+	}
+
+	changed := len(queued) > 0
+	for _, id := range removed {
+		if q.Remove(id) {
+			changed = true
+		}
+	}
+	for _, e := range queued {
+		q.Upsert(e)
+	}
+	w.queue = q
+
+	if changed && !w.dryRun {
+		return q.Save(w.queuePath)
+	}
+	return nil
+}
+
+// classifyWithContent reclassifica f pelo conteúdo, mantendo s se não for
+// possível.
+func (w *watcher) classifyWithContent(ctx context.Context, f *drive.FileInfo, s *classifier.Suggestion) *classifier.Suggestion {
+	cs, err := classifyWithContent(ctx, w.fetcher, w.cls, w.cache, f, w.folders)
+	switch {
+	case err == nil:
+		if !cs.Offline {
+			w.cache.Set(classifier.CacheKey(f.Md5Checksum, f.Name, f.Size, f.MimeType), cs)
+		}
+		return cs
+	case errors.Is(err, extract.ErrScanned):
+		slog.Info("PDF digitalizado, sem camada de texto", "file", f.Name)
+	default:
+		slog.Debug("classificação por conteúdo indisponível", "file", f.Name, "error", err)
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/classifier"
+	"github.com/vitoramaral10/driver-organizer/internal/config"
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+	"github.com/vitoramaral10/driver-organizer/internal/watch"
+)
+
+func TestWatchQueueKeepsReviewEdits(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	queuePath := filepath.Join(t.TempDir(), "watch_queue.json")
+	queue, err := loadWatchQueue(queuePath, "drive")
+	if err != nil {
+		t.Fatalf("loadWatchQueue: %v", err)
+	}
+	w := &watcher{backend: m, queue: queue, queuePath: queuePath}
+
+	reviewed := plan.NewEntry(m.AddFile("nota.pdf", "application/pdf", 1), "nota.pdf")
+	skipped := plan.NewEntry(m.AddFile("foto.jpg", "image/jpeg", 1), "foto.jpg")
+	if err := w.updateQueue(nil, []plan.Entry{reviewed, skipped}); err != nil {
+		t.Fatalf("updateQueue: %v", err)
+	}
+
+	// Enquanto o watch classifica o próximo lote, o serve edita e pula entradas
+	p, err := plan.Load(queuePath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	p.Entries[0].TargetFolder = "Financeiro"
+	p.Entries[0].Approved = true
+	p.Remove(skipped.FileID)
+	if err := p.Save(queuePath); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	added := plan.NewEntry(m.AddFile("contrato.pdf", "application/pdf", 1), "contrato.pdf")
+	if err := w.updateQueue(nil, []plan.Entry{added}); err != nil {
+		t.Fatalf("updateQueue: %v", err)
+	}
+
+	p, err = plan.Load(queuePath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(p.Entries) != 2 {
+		t.Fatalf("fila com %d entradas, want 2: %+v", len(p.Entries), p.Entries)
+	}
+	if e := p.Entries[0]; e.FileID != reviewed.FileID || e.TargetFolder != "Financeiro" || !e.Approved {
+		t.Errorf("edição do serve perdida: %+v", e)
+	}
+	if p.Entries[1].FileID != added.FileID {
+		t.Errorf("entrada nova = %s, want %s", p.Entries[1].FileID, added.FileID)
+	}
+}
+
+func TestWatchRetryDoesNotReplayBatch(t *testing.T) {
+	orig := cfg
+	t.Cleanup(func() { cfg = orig })
+	cfg = config.DefaultConfig()
+	cfg.WatchAuto = true
+	cfg.MinConfidence = 0.8
+
+	m := drive.NewMemoryBackend()
+	boleto := m.AddFile("boleto_luz.pdf", "application/pdf", 100)
+	notes := m.AddFile("notas.txt", "text/plain", 10)
+
+	// A fila fica em um diretório que ainda não existe: gravar falha
+	queuePath := filepath.Join(t.TempDir(), "fila", "watch_queue.json")
+	queue, err := loadWatchQueue(queuePath, "drive")
+	if err != nil {
+		t.Fatalf("loadWatchQueue: %v", err)
+	}
+	w := &watcher{
+		backend:   m,
+		cls:       classifier.NewOffline(),
+		cache:     classifier.NewCache(),
+		fetcher:   newContentFetcher(m),
+		queue:     queue,
+		queuePath: queuePath,
+	}
+	batch := watch.Batch{Files: []*drive.FileInfo{boleto, notes}}
+
+	ctx := context.Background()
+	if err := w.handle(ctx, batch); err == nil {
+		t.Fatal("handle com a fila inacessível deveria falhar")
+	}
+	assertIn(t, m, boleto.ID, "Financeiro/Boletos")
+
+	// A página volta na próxima consulta
+	os.MkdirAll(filepath.Dir(queuePath), 0755)
+	if err := w.handle(ctx, batch); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	if w.applied != 1 || w.queued != 1 {
+		t.Errorf("aplicados %d, na fila %d; want 1 e 1", w.applied, w.queued)
+	}
+	p, err := plan.Load(queuePath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(p.Entries) != 1 || p.Entries[0].FileID != notes.ID {
+		t.Errorf("fila = %+v, want só notas.txt", p.Entries)
+	}
+}
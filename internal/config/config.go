@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
+	Profile         string  `mapstructure:"profile"`
 	CredentialsPath string  `mapstructure:"credentials_path"`
 	TokenPath       string  `mapstructure:"token_path"`
 	GeminiAPIKey    string  `mapstructure:"gemini_api_key"`
@@ -17,9 +21,67 @@ type Config struct {
 	BackupFolder    string  `mapstructure:"backup_folder"`
 	BatchSize       int     `mapstructure:"batch_size"`
 	RateLimit       int     `mapstructure:"rate_limit"`
+	AIRateLimit     int     `mapstructure:"ai_rate_limit"`
 	MaxCost         float64 `mapstructure:"max_cost"`
 	LogLevel        string  `mapstructure:"log_level"`
 	DryRun          bool    `mapstructure:"dry_run"`
+	Backend         string  `mapstructure:"backend"`
+	LocalRoot       string  `mapstructure:"local_root"`
+	SharedDrive     string  `mapstructure:"shared_drive"`
+	AIProvider      string  `mapstructure:"ai_provider"`
+	OpenAIBaseURL   string  `mapstructure:"openai_base_url"`
+	OpenAIAPIKey    string  `mapstructure:"openai_api_key"`
+	OpenAIModel     string  `mapstructure:"openai_model"`
+	Classifier      string  `mapstructure:"classifier"`
+	OfflineFallback bool    `mapstructure:"offline_fallback"`
+	MinConfidence   float64 `mapstructure:"min_confidence"`
+	ContentMaxBytes int64   `mapstructure:"content_max_bytes"`
+	ContentBudget   int64   `mapstructure:"content_budget"`
+	ContentMaxPages int     `mapstructure:"content_max_pages"`
+	ImageClassify   bool    `mapstructure:"image_classification"`
+	MaxImageCalls   int     `mapstructure:"max_image_calls"`
+	UI              string  `mapstructure:"ui"`
+
+	// Watch: pasta de entrada observada (vazia = raiz), intervalo entre
+	// consultas e aplicação automática das sugestões confiáveis
+	WatchInbox    string        `mapstructure:"watch_inbox"`
+	WatchInterval time.Duration `mapstructure:"watch_interval"`
+	WatchAuto     bool          `mapstructure:"watch_auto"`
+
+	// Pricing sobrescreve os preços por modelo (USD por milhão de tokens)
+	Pricing map[string]ModelPricing `mapstructure:"pricing"`
+
+	// Rules são avaliadas em ordem antes da IA
+	Rules []Rule `mapstructure:"rules"`
+}
+
+// Rule é uma regra declarativa de organização: arquivos que atendem a todas
+// as condições de Match vão para Folder, renomeados por Rename.
+type Rule struct {
+	Name   string    `mapstructure:"name"`
+	Match  RuleMatch `mapstructure:"match"`
+	Folder string    `mapstructure:"folder"`
+	Rename string    `mapstructure:"rename"`
+}
+
+// RuleMatch são as condições de uma regra; campos vazios não restringem.
+type RuleMatch struct {
+	Name           string `mapstructure:"name"`
+	NameRegex      string `mapstructure:"name_regex"`
+	MimeType       string `mapstructure:"mime_type"`
+	MinSize        string `mapstructure:"min_size"`
+	MaxSize        string `mapstructure:"max_size"`
+	CreatedAfter   string `mapstructure:"created_after"`
+	CreatedBefore  string `mapstructure:"created_before"`
+	ModifiedAfter  string `mapstructure:"modified_after"`
+	ModifiedBefore string `mapstructure:"modified_before"`
+	Folder         string `mapstructure:"folder"`
+}
+
+// ModelPricing é o preço de um modelo em USD por milhão de tokens.
+type ModelPricing struct {
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
 }
 
 func DefaultConfig() *Config {
@@ -32,9 +94,29 @@ func DefaultConfig() *Config {
 		BackupFolder:    "backup",
 		BatchSize:       20,
 		RateLimit:       10,
+		AIRateLimit:     15,
 		MaxCost:         5.0,
 		LogLevel:        "info",
 		DryRun:          false,
+		Backend:         "drive",
+		LocalRoot:       "",
+		SharedDrive:     "",
+		AIProvider:      "gemini",
+		OpenAIBaseURL:   "http://localhost:11434/v1",
+		OpenAIAPIKey:    "",
+		OpenAIModel:     "llama3.1",
+		Classifier:      "ai",
+		OfflineFallback: true,
+		MinConfidence:   0.85,
+		ContentMaxBytes: 10 << 20,
+		ContentBudget:   100 << 20,
+		ContentMaxPages: 5,
+		ImageClassify:   false,
+		MaxImageCalls:   20,
+		UI:              "plain",
+		WatchInbox:      "",
+		WatchInterval:   time.Minute,
+		WatchAuto:       false,
 	}
 }
 
@@ -44,9 +126,34 @@ func ConfigDir() string {
 	return filepath.Join(home, ".config", "driver-organizer")
 }
 
-// GeminiKeyPath retorna o caminho do arquivo que armazena a API key do Gemini.
+// GeminiKeyPath retorna o caminho do arquivo que armazena a API key do Gemini
+// (do perfil ativo, se houver).
 func GeminiKeyPath() string {
-	return filepath.Join(ConfigDir(), "gemini_api_key")
+	return filepath.Join(dataDir(), "gemini_api_key")
+}
+
+// CachePath retorna o caminho do cache persistente de classificações do perfil
+// ativo.
+func CachePath() string {
+	return filepath.Join(dataDir(), "classification_cache.jsonl")
+}
+
+// JournalPath retorna o caminho do diário de operações usado pelo undo, no
+// diretório do perfil ativo.
+func JournalPath() string {
+	return filepath.Join(dataDir(), "journal.jsonl")
+}
+
+// WatchStatePath retorna o caminho do estado do watch (token da API de
+// mudanças) do perfil ativo.
+func WatchStatePath() string {
+	return filepath.Join(dataDir(), "watch_state.json")
+}
+
+// WatchQueuePath retorna o caminho padrão da fila de revisão do watch, um
+// plano no formato de "organize plan".
+func WatchQueuePath() string {
+	return filepath.Join(dataDir(), "watch_queue.json")
 }
 
 // LoadGeminiAPIKey carrega a API key salva em disco.
@@ -64,7 +171,7 @@ func LoadGeminiAPIKey() (string, error) {
 
 // SaveGeminiAPIKey salva a API key em disco.
 func SaveGeminiAPIKey(key string) error {
-	dir := ConfigDir()
+	dir := dataDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return fmt.Errorf("erro ao criar diretório de config: %w", err)
 	}
@@ -98,9 +205,29 @@ func Load(cfgFile string) (*Config, error) {
 	viper.SetDefault("backup_folder", cfg.BackupFolder)
 	viper.SetDefault("batch_size", cfg.BatchSize)
 	viper.SetDefault("rate_limit", cfg.RateLimit)
+	viper.SetDefault("ai_rate_limit", cfg.AIRateLimit)
 	viper.SetDefault("max_cost", cfg.MaxCost)
 	viper.SetDefault("log_level", cfg.LogLevel)
 	viper.SetDefault("dry_run", cfg.DryRun)
+	viper.SetDefault("backend", cfg.Backend)
+	viper.SetDefault("local_root", cfg.LocalRoot)
+	viper.SetDefault("shared_drive", cfg.SharedDrive)
+	viper.SetDefault("ai_provider", cfg.AIProvider)
+	viper.SetDefault("openai_base_url", cfg.OpenAIBaseURL)
+	viper.SetDefault("openai_api_key", cfg.OpenAIAPIKey)
+	viper.SetDefault("openai_model", cfg.OpenAIModel)
+	viper.SetDefault("classifier", cfg.Classifier)
+	viper.SetDefault("offline_fallback", cfg.OfflineFallback)
+	viper.SetDefault("min_confidence", cfg.MinConfidence)
+	viper.SetDefault("content_max_bytes", cfg.ContentMaxBytes)
+	viper.SetDefault("content_budget", cfg.ContentBudget)
+	viper.SetDefault("content_max_pages", cfg.ContentMaxPages)
+	viper.SetDefault("image_classification", cfg.ImageClassify)
+	viper.SetDefault("max_image_calls", cfg.MaxImageCalls)
+	viper.SetDefault("ui", cfg.UI)
+	viper.SetDefault("watch_inbox", cfg.WatchInbox)
+	viper.SetDefault("watch_interval", cfg.WatchInterval)
+	viper.SetDefault("watch_auto", cfg.WatchAuto)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -108,9 +235,35 @@ func Load(cfgFile string) (*Config, error) {
 		}
 	}
 
-	if err := viper.Unmarshal(cfg); err != nil {
+	// Perfil (--profile, DORGANIZER_PROFILE ou "profile" no config.yaml)
+	activeProfile = ""
+	if name := viper.GetString("profile"); name != "" {
+		if err := loadProfile(name); err != nil {
+			return nil, err
+		}
+	}
+
+	hooks := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		dateToStringHook,
+	))
+	if err := viper.Unmarshal(cfg, hooks); err != nil {
 		return nil, fmt.Errorf("erro ao decodificar config: %w", err)
 	}
 
 	return cfg, nil
 }
+
+// dateToStringHook devolve como texto as datas sem aspas do YAML (ex:
+// created_after: 2024-01-01), que o parser entrega como time.Time.
+func dateToStringHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	t, ok := data.(time.Time)
+	if !ok || to.Kind() != reflect.String {
+		return data, nil
+	}
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02"), nil
+	}
+	return t.Format(time.RFC3339), nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// activeProfile é o perfil selecionado em Load; vazio usa o diretório de
+// configuração principal.
+var activeProfile string
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Profile é uma conta configurada separadamente (ex: pessoal e trabalho), com
+// token, API key do Gemini, cache e diário próprios.
+type Profile struct {
+	Name         string
+	Dir          string
+	HasToken     bool
+	HasGeminiKey bool
+	BackupFolder string
+}
+
+// ValidateProfileName confere se o nome pode ser usado como diretório.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("nome de perfil inválido '%s' (use letras, números, '-' e '_')", name)
+	}
+	return nil
+}
+
+// ActiveProfile retorna o perfil em uso, ou "" sem perfil.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// ProfilesDir retorna o diretório que contém os perfis.
+func ProfilesDir() string {
+	return filepath.Join(ConfigDir(), "profiles")
+}
+
+// ProfileDir retorna o diretório de um perfil.
+func ProfileDir(name string) string {
+	return filepath.Join(ProfilesDir(), name)
+}
+
+// ProfileExists informa se o perfil já foi criado.
+func ProfileExists(name string) bool {
+	info, err := os.Stat(ProfileDir(name))
+	return err == nil && info.IsDir()
+}
+
+// dataDir é onde ficam o token, a API key, o cache e o diário: o diretório
+// do perfil ativo ou o diretório de configuração principal.
+func dataDir() string {
+	if activeProfile != "" {
+		return ProfileDir(activeProfile)
+	}
+	return ConfigDir()
+}
+
+// profileConfigPath retorna o config.yaml de um perfil, que sobrepõe a
+// configuração global.
+func profileConfigPath(name string) string {
+	return filepath.Join(ProfileDir(name), "config.yaml")
+}
+
+// CreateProfile cria o diretório do perfil e um config.yaml com a pasta de
+// backup, se ainda não existir.
+func CreateProfile(name, backupFolder string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ProfileDir(name), 0700); err != nil {
+		return fmt.Errorf("erro ao criar perfil '%s': %w", name, err)
+	}
+
+	path := profileConfigPath(name)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	content := fmt.Sprintf("# Configuração do perfil %q; sobrepõe ~/.config/driver-organizer/config.yaml\nbackup_folder: %q\n",
+		name, backupFolder)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("erro ao criar configuração do perfil '%s': %w", name, err)
+	}
+	return nil
+}
+
+// ListProfiles lista os perfis criados, em ordem alfabética.
+func ListProfiles() ([]Profile, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar perfis: %w", err)
+	}
+
+	var profiles []Profile
+	for _, e := range entries {
+		if !e.IsDir() || ValidateProfileName(e.Name()) != nil {
+			continue
+		}
+		p := Profile{Name: e.Name(), Dir: ProfileDir(e.Name())}
+
+		v := viper.New()
+		v.SetConfigFile(profileConfigPath(p.Name))
+		if err := v.ReadInConfig(); err == nil {
+			p.BackupFolder = v.GetString("backup_folder")
+		}
+		tokenPath := filepath.Join(p.Dir, "token.json")
+		if v.IsSet("token_path") {
+			tokenPath = v.GetString("token_path")
+		}
+		p.HasToken = fileExists(tokenPath)
+		p.HasGeminiKey = fileExists(filepath.Join(p.Dir, "gemini_api_key")) || v.GetString("gemini_api_key") != ""
+
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// RemoveProfile apaga o diretório do perfil, com token, API key, cache e
+// diário.
+func RemoveProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("perfil '%s' não encontrado", name)
+	}
+	if err := os.RemoveAll(ProfileDir(name)); err != nil {
+		return fmt.Errorf("erro ao remover perfil '%s': %w", name, err)
+	}
+	return nil
+}
+
+// loadProfile ativa o perfil e mescla o config.yaml dele sobre a configuração
+// global já lida. O token fica no diretório do perfil, a menos que token_path
+// venha de uma flag, de DORGANIZER_TOKEN_PATH ou de um dos config.yaml.
+func loadProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	activeProfile = name
+
+	path := profileConfigPath(name)
+	if _, err := os.Stat(path); err == nil {
+		v := viper.New()
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("erro ao ler config do perfil '%s': %w", name, err)
+		}
+		if err := viper.MergeConfigMap(v.AllSettings()); err != nil {
+			return fmt.Errorf("erro ao mesclar config do perfil '%s': %w", name, err)
+		}
+	}
+
+	viper.SetDefault("token_path", filepath.Join(ProfileDir(name), "token.json"))
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProfileTokenPathPrecedence(t *testing.T) {
+	tests := []struct {
+		name          string
+		globalConfig  string
+		profileConfig string
+		env           string
+		want          string
+	}{
+		{"padrão do perfil", "", "", "", "profiles/trabalho/token.json"},
+		{"variável de ambiente", "", "", "/env/token.json", "/env/token.json"},
+		{"config global", "token_path: /global/token.json\n", "", "", "/global/token.json"},
+		{"config do perfil sobre a global", "token_path: /global/token.json\n", "token_path: /perfil/token.json\n", "", "/perfil/token.json"},
+		{"variável de ambiente sobre os arquivos", "token_path: /global/token.json\n", "token_path: /perfil/token.json\n", "/env/token.json", "/env/token.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("DORGANIZER_PROFILE", "trabalho")
+			t.Setenv("DORGANIZER_TOKEN_PATH", tt.env)
+			if tt.env == "" {
+				os.Unsetenv("DORGANIZER_TOKEN_PATH")
+			}
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+
+			if err := CreateProfile("trabalho", "backup"); err != nil {
+				t.Fatalf("CreateProfile: %v", err)
+			}
+			if tt.globalConfig != "" {
+				writeFile(t, filepath.Join(ConfigDir(), "config.yaml"), tt.globalConfig)
+			}
+			if tt.profileConfig != "" {
+				writeFile(t, profileConfigPath("trabalho"), tt.profileConfig)
+			}
+
+			cfg, err := Load("")
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			want := tt.want
+			if !filepath.IsAbs(want) {
+				want = filepath.Join(ConfigDir(), want)
+			}
+			if cfg.TokenPath != want {
+				t.Errorf("TokenPath = %s, want %s", cfg.TokenPath, want)
+			}
+			if ActiveProfile() != "trabalho" {
+				t.Errorf("perfil ativo = %q, want trabalho", ActiveProfile())
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// Regras de resolução: qual cópia de cada grupo é mantida.
+const (
+	// KeepNewest mantém a cópia modificada por último.
+	KeepNewest = "newest"
+	// KeepOrganized mantém a cópia que está em uma pasta organizada (fora da
+	// raiz e da pasta de backup); sem nenhuma, mantém a mais recente.
+	KeepOrganized = "organized"
+)
+
+// Item é um arquivo com o caminho dele a partir da pasta percorrida.
+type Item struct {
+	File *drive.FileInfo
+	Path string
+}
+
+// Group é um conjunto de arquivos com o mesmo conteúdo (MD5 e tamanho).
+type Group struct {
+	Md5Checksum string
+	Size        int64
+	Items       []Item
+}
+
+// Wasted é o espaço ocupado pelas cópias além da primeira.
+func (g Group) Wasted() int64 {
+	return g.Size * int64(len(g.Items)-1)
+}
+
+// Find agrupa os arquivos idênticos. Arquivos são comparados primeiro pelo
+// tamanho; só os que têm tamanho repetido e não trazem checksum na listagem
+// (ex: backend local) têm o MD5 calculado pelo backend. Arquivos vazios e
+// documentos do Google, sem checksum, são ignorados. Os grupos saem em ordem
+// decrescente de espaço desperdiçado.
+func Find(ctx context.Context, b drive.Backend, items []Item) ([]Group, error) {
+	bySize := make(map[int64][]Item)
+	for _, it := range items {
+		if it.File.Size == 0 || it.File.IsFolder() || strings.HasPrefix(it.File.MimeType, "application/vnd.google-apps.") {
+			continue
+		}
+		bySize[it.File.Size] = append(bySize[it.File.Size], it)
+	}
+
+	byKey := make(map[string]*Group)
+	for size, candidates := range bySize {
+		if len(candidates) < 2 {
+			continue
+		}
+		for _, it := range candidates {
+			sum := it.File.Md5Checksum
+			if sum == "" {
+				var err error
+				sum, err = b.Checksum(ctx, it.File.ID)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil, err
+					}
+					slog.Warn("erro ao calcular checksum, ignorando arquivo", "path", it.Path, "error", err)
+					continue
+				}
+				if sum == "" {
+					continue
+				}
+				it.File.Md5Checksum = sum
+			}
+
+			key := fmt.Sprintf("%s|%d", sum, size)
+			g, ok := byKey[key]
+			if !ok {
+				g = &Group{Md5Checksum: sum, Size: size}
+				byKey[key] = g
+			}
+			g.Items = append(g.Items, it)
+		}
+	}
+
+	var groups []Group
+	for _, g := range byKey {
+		if len(g.Items) < 2 {
+			continue
+		}
+		slices.SortFunc(g.Items, func(a, b Item) int { return strings.Compare(a.Path, b.Path) })
+		groups = append(groups, *g)
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		if a.Wasted() != b.Wasted() {
+			if a.Wasted() > b.Wasted() {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Items[0].Path, b.Items[0].Path)
+	})
+	return groups, nil
+}
+
+// TotalWasted soma o espaço desperdiçado de todos os grupos.
+func TotalWasted(groups []Group) int64 {
+	var total int64
+	for _, g := range groups {
+		total += g.Wasted()
+	}
+	return total
+}
+
+// Keep escolhe, pela regra informada, o índice da cópia de g a manter.
+// unorganized lista as pastas de primeiro nível que não contam como
+// organizadas (ex: a pasta de backup); arquivos na raiz também não contam.
+func Keep(g Group, rule string, unorganized []string) (int, error) {
+	switch rule {
+	case KeepNewest:
+		return newest(g.Items, nil), nil
+	case KeepOrganized:
+		return newest(g.Items, func(it Item) bool { return IsOrganized(it.Path, unorganized) }), nil
+	default:
+		return 0, fmt.Errorf("regra desconhecida '%s' (use %s ou %s)", rule, KeepNewest, KeepOrganized)
+	}
+}
+
+// IsOrganized informa se o arquivo em p está em uma pasta organizada: dentro
+// de alguma pasta que não seja uma das unorganized.
+func IsOrganized(p string, unorganized []string) bool {
+	dir := path.Dir(p)
+	if dir == "." || dir == "/" {
+		return false
+	}
+	top := strings.Split(strings.TrimPrefix(dir, "/"), "/")[0]
+	return !slices.Contains(unorganized, top)
+}
+
+// newest retorna o índice do item modificado por último entre os que
+// satisfazem prefer; sem nenhum, considera todos. Datas RFC 3339 em UTC
+// podem ser comparadas como texto.
+func newest(items []Item, prefer func(Item) bool) int {
+	best := -1
+	for i, it := range items {
+		if prefer != nil && !prefer(it) {
+			continue
+		}
+		if best < 0 || it.File.ModifiedTime > items[best].File.ModifiedTime {
+			best = i
+		}
+	}
+	if best < 0 {
+		return newest(items, nil)
+	}
+	return best
+}
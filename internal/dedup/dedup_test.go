@@ -0,0 +1,95 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func walk(t *testing.T, m *drive.MemoryBackend) []Item {
+	t.Helper()
+	var items []Item
+	err := drive.WalkFiles(context.Background(), m, m.RootID(), "", func(f *drive.FileInfo, p string) {
+		items = append(items, Item{File: f, Path: p})
+	})
+	if err != nil {
+		t.Fatalf("WalkFiles: %v", err)
+	}
+	return items
+}
+
+func TestFindGroupsIdenticalFiles(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	backup := m.AddFolder("backup")
+	docs := m.AddFolder("Documentos")
+
+	content := []byte("conteúdo repetido")
+	for _, parent := range []string{m.RootID(), backup.ID, docs.ID} {
+		f := m.AddFile("contrato.pdf", "application/pdf", int64(len(content)), parent)
+		m.SetContent(f.ID, content)
+	}
+	// Mesmo tamanho, conteúdo diferente
+	other := m.AddFile("outro.pdf", "application/pdf", int64(len(content)), docs.ID)
+	m.SetContent(other.ID, []byte("conteúdo diferente"))
+	// Arquivos vazios são ignorados
+	m.AddFile("vazio.txt", "text/plain", 0)
+	m.AddFile("vazio2.txt", "text/plain", 0)
+
+	groups, err := Find(context.Background(), m, walk(t, m))
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Find retornou %d grupos, want 1", len(groups))
+	}
+	g := groups[0]
+	if len(g.Items) != 3 {
+		t.Fatalf("grupo com %d itens, want 3", len(g.Items))
+	}
+	if g.Wasted() != 2*int64(len(content)) {
+		t.Errorf("Wasted = %d, want %d", g.Wasted(), 2*len(content))
+	}
+
+	i, err := Keep(g, KeepOrganized, []string{"backup"})
+	if err != nil {
+		t.Fatalf("Keep: %v", err)
+	}
+	if g.Items[i].Path != "Documentos/contrato.pdf" {
+		t.Errorf("Keep(organized) manteve %s, want Documentos/contrato.pdf", g.Items[i].Path)
+	}
+
+	for j, it := range g.Items {
+		if j == i {
+			continue
+		}
+		if err := m.TrashFile(context.Background(), it.File.ID); err != nil {
+			t.Fatalf("TrashFile: %v", err)
+		}
+	}
+	groups, err = Find(context.Background(), m, walk(t, m))
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("depois de remover as cópias, Find retornou %d grupos", len(groups))
+	}
+}
+
+func TestIsOrganized(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"contrato.pdf", false},
+		{"backup/contrato.pdf", false},
+		{"backup/Documentos/contrato.pdf", false},
+		{"Documentos/contrato.pdf", true},
+		{"Documentos/Contratos/contrato.pdf", true},
+	}
+	for _, tt := range tests {
+		if got := IsOrganized(tt.path, []string{"backup"}); got != tt.want {
+			t.Errorf("IsOrganized(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/vitoramaral10/driver-organizer/internal/ratelimit"
+)
+
+// Backend abstrai as operações de armazenamento usadas pelo organizador.
+// GoogleBackend fala com a API do Drive, LocalBackend com um diretório local
+// e MemoryBackend mantém tudo em memória.
+type Backend interface {
+	// RootID retorna o ID da pasta raiz do backend.
+	RootID() string
+
+	// ListFilesPage lista uma página dos itens de uma pasta. Retorna o token
+	// da próxima página, ou "" quando não houver mais páginas.
+	ListFilesPage(ctx context.Context, folderID string, pageToken string) ([]*FileInfo, string, error)
+
+	// ListFolders lista todas as pastas dentro de um parent.
+	ListFolders(ctx context.Context, parentID string) ([]*FileInfo, error)
+
+	// FindFolderByName procura uma pasta pelo nome dentro de um parent.
+	// Retorna nil, nil se a pasta não existir.
+	FindFolderByName(ctx context.Context, name string, parentID string) (*FileInfo, error)
+
+	// CreateFolder cria uma pasta dentro de um parent.
+	CreateFolder(ctx context.Context, name string, parentID string) (*FileInfo, error)
+
+	// MoveFile move um item de uma pasta para outra.
+	MoveFile(ctx context.Context, fileID string, newParentID string, oldParentID string) (*FileInfo, error)
+
+	// RenameFile renomeia um item.
+	RenameFile(ctx context.Context, fileID string, newName string) (*FileInfo, error)
+
+	// MoveAndRenameFile move e renomeia um item em uma única operação.
+	MoveAndRenameFile(ctx context.Context, fileID string, newName string, newParentID string, oldParentID string) (*FileInfo, error)
+
+	// GetFile retorna os metadados atuais de um item.
+	GetFile(ctx context.Context, fileID string) (*FileInfo, error)
+
+	// TrashFile move um item para a lixeira.
+	TrashFile(ctx context.Context, fileID string) error
+
+	// Download retorna até maxBytes do conteúdo de um arquivo. Documentos do
+	// Google (mimeType application/vnd.google-apps.*) são exportados como texto.
+	Download(ctx context.Context, fileID string, mimeType string, maxBytes int64) ([]byte, error)
+
+	// Thumbnail retorna a miniatura de um arquivo e o tipo MIME dela, ou
+	// ErrNoThumbnail se não houver miniatura.
+	Thumbnail(ctx context.Context, fileID string, maxBytes int64) ([]byte, string, error)
+
+	// Checksum retorna o MD5 (hexadecimal) do conteúdo de um arquivo, para
+	// backends que não o informam nas listagens. Retorna "" para documentos
+	// do Google, que não têm checksum.
+	Checksum(ctx context.Context, fileID string) (string, error)
+}
+
+// GoogleBackend implementa Backend sobre a API do Google Drive.
+type GoogleBackend struct {
+	srv     *drive.Service
+	client  *http.Client
+	limiter *ratelimit.Limiter
+
+	// driveID é o drive compartilhado em uso; vazio para o "Meu Drive"
+	driveID string
+
+	folders folderCache
+}
+
+// NewGoogleBackend cria um backend para o "Meu Drive" do usuário autenticado.
+func NewGoogleBackend(srv *drive.Service) *GoogleBackend {
+	return &GoogleBackend{srv: srv}
+}
+
+// NewGoogleBackendFromClient cria o backend a partir do cliente HTTP
+// autenticado, que também é usado para baixar as miniaturas (thumbnailLink),
+// servidas fora da API.
+func NewGoogleBackendFromClient(ctx context.Context, client *http.Client) (*GoogleBackend, error) {
+	srv, err := newDriveService(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleBackend{srv: srv, client: client}, nil
+}
+
+// SetRateLimiter faz cada chamada à API (inclusive as repetições do backoff)
+// esperar por uma permissão de l.
+func (g *GoogleBackend) SetRateLimiter(l *ratelimit.Limiter) {
+	g.limiter = l
+}
+
+// UseSharedDrive passa a operar no drive compartilhado informado pelo nome ou
+// pelo ID, e retorna o drive encontrado. A raiz do backend passa a ser a raiz
+// do drive compartilhado, cujo ID é o próprio ID do drive.
+func (g *GoogleBackend) UseSharedDrive(ctx context.Context, nameOrID string) (*drive.Drive, error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	d, err := g.srv.Drives.Get(nameOrID).Context(ctx).Fields("id, name").Do()
+	if err != nil {
+		// IDs inválidos (ex: um nome) retornam 404 ou 400
+		if apiErr, ok := err.(*googleapi.Error); !ok || (apiErr.Code != http.StatusNotFound && apiErr.Code != http.StatusBadRequest) {
+			return nil, fmt.Errorf("erro ao buscar drive compartilhado '%s': %w", nameOrID, err)
+		}
+		d, err = g.findSharedDrive(ctx, nameOrID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g.driveID = d.Id
+	g.folders.reset()
+	slog.Info("usando drive compartilhado", "name", d.Name, "id", d.Id)
+	return d, nil
+}
+
+// findSharedDrive procura um drive compartilhado pelo nome exato.
+func (g *GoogleBackend) findSharedDrive(ctx context.Context, name string) (*drive.Drive, error) {
+	var found []*drive.Drive
+	pageToken := ""
+	for {
+		if err := g.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		req := g.srv.Drives.List().
+			Context(ctx).
+			Q(fmt.Sprintf("name = '%s'", escapeDriveQuery(name))).
+			PageSize(100).
+			Fields("nextPageToken, drives(id, name)")
+		if pageToken != "" {
+			req = req.PageToken(pageToken)
+		}
+		result, err := req.Do()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar drives compartilhados: %w", err)
+		}
+		found = append(found, result.Drives...)
+
+		pageToken = result.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("drive compartilhado '%s' não encontrado", name)
+	case 1:
+		return found[0], nil
+	default:
+		ids := make([]string, len(found))
+		for i, d := range found {
+			ids[i] = d.Id
+		}
+		return nil, fmt.Errorf("há %d drives compartilhados chamados '%s'; use o ID (%s)",
+			len(found), name, strings.Join(ids, ", "))
+	}
+}
+
+// SharedDriveID retorna o ID do drive compartilhado em uso, ou "" no "Meu Drive".
+func (g *GoogleBackend) SharedDriveID() string {
+	return g.driveID
+}
+
+// RootID retorna a raiz do drive compartilhado em uso ou o alias da raiz do
+// "Meu Drive".
+func (g *GoogleBackend) RootID() string {
+	if g.driveID != "" {
+		return g.driveID
+	}
+	return "root"
+}
+
+// list prepara uma listagem de arquivos restrita ao drive em uso.
+func (g *GoogleBackend) list(ctx context.Context) *drive.FilesListCall {
+	req := g.srv.Files.List().
+		Context(ctx).
+		SupportsAllDrives(true)
+	if g.driveID != "" {
+		req = req.IncludeItemsFromAllDrives(true).
+			Corpora("drive").
+			DriveId(g.driveID)
+	}
+	return req
+}
+
+func fileInfoFromDrive(f *drive.File) *FileInfo {
+	info := &FileInfo{
+		ID:           f.Id,
+		Name:         f.Name,
+		MimeType:     f.MimeType,
+		Parents:      f.Parents,
+		CreatedTime:  f.CreatedTime,
+		ModifiedTime: f.ModifiedTime,
+		Size:         f.Size,
+		Md5Checksum:  f.Md5Checksum,
+		Trashed:      f.Trashed,
+		WebViewLink:  f.WebViewLink,
+		Shared:       f.Shared,
+	}
+	for _, o := range f.Owners {
+		info.Owners = append(info.Owners, o.EmailAddress)
+	}
+	if c := f.Capabilities; c != nil {
+		info.Capabilities = &Capabilities{
+			CanMove:   c.CanMoveItemWithinDrive,
+			CanRename: c.CanRename,
+			CanTrash:  c.CanTrash,
+		}
+	}
+	return info
+}
+
+// pageOf recorta uma página de uma listagem já ordenada. Usado pelos backends
+// que não têm paginação nativa; o token é o deslocamento da próxima página.
+func pageOf(items []*FileInfo, pageToken string, pageSize int) ([]*FileInfo, string, error) {
+	offset := 0
+	if pageToken != "" {
+		n, err := strconv.Atoi(pageToken)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("erro ao listar arquivos: pageToken inválido '%s'", pageToken)
+		}
+		offset = n
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+
+	end := offset + pageSize
+	if end >= len(items) {
+		return items[offset:], "", nil
+	}
+	return items[offset:end], strconv.Itoa(end), nil
+}
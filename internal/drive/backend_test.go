@@ -0,0 +1,50 @@
+package drive
+
+import (
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+func TestFileInfoFromDriveCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		caps *drive.FileCapabilities
+		want bool
+	}{
+		{"sem capabilities", nil, true},
+		{"pode mover", &drive.FileCapabilities{CanMoveItemWithinDrive: true, CanRename: true}, true},
+		{"leitor de drive compartilhado", &drive.FileCapabilities{CanRename: true}, false},
+	}
+	for _, tt := range tests {
+		f := fileInfoFromDrive(&drive.File{Id: "1", Name: "a.pdf", Capabilities: tt.caps})
+		if got := f.CanMove(); got != tt.want {
+			t.Errorf("%s: CanMove = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGoogleBackendRootID(t *testing.T) {
+	var g GoogleBackend
+	if id := g.RootID(); id != "root" {
+		t.Errorf("RootID no Meu Drive = %q, want root", id)
+	}
+	g.driveID = "0AbcDrive"
+	if id := g.RootID(); id != "0AbcDrive" || g.SharedDriveID() != "0AbcDrive" {
+		t.Errorf("RootID no drive compartilhado = %q, want o ID do drive", id)
+	}
+}
+
+func TestEscapeDriveQuery(t *testing.T) {
+	tests := map[string]string{
+		"Equipe":       "Equipe",
+		"Drive d'Água": `Drive d\'Água`,
+		`C:\Backups`:   `C:\\Backups`,
+		`a\'b`:         `a\\\'b`,
+	}
+	for in, want := range tests {
+		if got := escapeDriveQuery(in); got != want {
+			t.Errorf("escapeDriveQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// Change é uma alteração reportada pela API de mudanças do Drive.
+type Change struct {
+	FileID string
+
+	// Removed indica que o item foi apagado ou deixou de ser acessível;
+	// nesse caso File é nil.
+	Removed bool
+	File    *FileInfo
+}
+
+// ChangeSource é implementado pelos backends que informam as alterações de
+// forma incremental, a partir de um token (hoje só o Google Drive).
+type ChangeSource interface {
+	// StartPageToken retorna o token a partir do qual as próximas
+	// alterações serão reportadas.
+	StartPageToken(ctx context.Context) (string, error)
+
+	// ChangesPage lista uma página de alterações a partir de pageToken.
+	// Retorna o token da próxima página ou, na última, o novo token inicial
+	// para consultas futuras.
+	ChangesPage(ctx context.Context, pageToken string) (changes []Change, nextPageToken string, newStartPageToken string, err error)
+}
+
+// StartPageToken retorna o token atual da API de mudanças do drive em uso.
+func (g *GoogleBackend) StartPageToken(ctx context.Context) (string, error) {
+	var token *drive.StartPageToken
+	err := g.retry(ctx, func() error {
+		req := g.srv.Changes.GetStartPageToken().
+			Context(ctx).
+			SupportsAllDrives(true)
+		if g.driveID != "" {
+			req = req.DriveId(g.driveID)
+		}
+		var err error
+		token, err = req.Do()
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter token de mudanças: %w", err)
+	}
+	return token.StartPageToken, nil
+}
+
+// ChangesPage lista uma página de alterações do drive em uso.
+func (g *GoogleBackend) ChangesPage(ctx context.Context, pageToken string) ([]Change, string, string, error) {
+	var result *drive.ChangeList
+	err := g.retry(ctx, func() error {
+		req := g.srv.Changes.List(pageToken).
+			Context(ctx).
+			Spaces("drive").
+			PageSize(100).
+			SupportsAllDrives(true).
+			Fields("nextPageToken, newStartPageToken, changes(fileId, removed, file(" + fileFields + "))")
+		if g.driveID != "" {
+			req = req.IncludeItemsFromAllDrives(true).DriveId(g.driveID)
+		} else {
+			req = req.RestrictToMyDrive(true)
+		}
+		var err error
+		result, err = req.Do()
+		return err
+	})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("erro ao listar mudanças: %w", err)
+	}
+
+	changes := make([]Change, 0, len(result.Changes))
+	for _, c := range result.Changes {
+		// Pastas alteradas fora da sessão saem do cache
+		g.folders.forget(c.FileId)
+		change := Change{FileID: c.FileId, Removed: c.Removed}
+		if c.File != nil && !c.Removed {
+			change.File = fileInfoFromDrive(c.File)
+		}
+		changes = append(changes, change)
+	}
+	return changes, result.NextPageToken, result.NewStartPageToken, nil
+}
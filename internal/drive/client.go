@@ -20,6 +20,16 @@ import (
 
 // NewService cria um novo serviço autenticado do Google Drive.
 func NewService(ctx context.Context, credentialsPath, tokenPath string) (*drive.Service, error) {
+	client, err := NewClient(ctx, credentialsPath, tokenPath)
+	if err != nil {
+		return nil, err
+	}
+	return newDriveService(ctx, client)
+}
+
+// NewClient cria o cliente HTTP autenticado com OAuth2, pedindo autorização
+// ao usuário se ainda não houver token salvo.
+func NewClient(ctx context.Context, credentialsPath, tokenPath string) (*http.Client, error) {
 	b, err := os.ReadFile(credentialsPath)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler credentials: %w\n\nBaixe o arquivo credentials.json do Google Cloud Console:\nhttps://console.cloud.google.com/apis/credentials", err)
@@ -58,7 +68,10 @@ func NewService(ctx context.Context, credentialsPath, tokenPath string) (*drive.
 	if err != nil {
 		return nil, fmt.Errorf("erro ao obter client OAuth2: %w", err)
 	}
+	return client, nil
+}
 
+func newDriveService(ctx context.Context, client *http.Client) (*drive.Service, error) {
 	srv, err := drive.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar serviço Drive: %w", err)
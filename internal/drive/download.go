@@ -0,0 +1,145 @@
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrNoThumbnail indica que o arquivo não tem miniatura ou que o backend não
+// gera miniaturas.
+var ErrNoThumbnail = errors.New("miniatura indisponível")
+
+// thumbnailSize é o lado maior, em pixels, pedido para as miniaturas do Drive.
+const thumbnailSize = 512
+
+var thumbnailSizeSuffix = regexp.MustCompile(`=s\d+$`)
+
+// exportMimeTypes mapeia os documentos do Google para o formato de texto usado
+// na exportação. Planilhas não exportam text/plain; usam CSV (primeira aba).
+var exportMimeTypes = map[string]string{
+	"application/vnd.google-apps.document":     "text/plain",
+	"application/vnd.google-apps.spreadsheet":  "text/csv",
+	"application/vnd.google-apps.presentation": "text/plain",
+}
+
+// IsExportable informa se o tipo é um documento do Google que Download
+// exporta como texto.
+func IsExportable(mimeType string) bool {
+	_, ok := exportMimeTypes[mimeType]
+	return ok
+}
+
+// Download baixa até maxBytes do conteúdo de um arquivo. Arquivos comuns usam
+// Files.Get com um cabeçalho Range; documentos do Google usam Files.Export.
+func (g *GoogleBackend) Download(ctx context.Context, fileID string, mimeType string, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return nil, nil
+	}
+
+	var data []byte
+	err := g.retry(ctx, func() error {
+		var resp *http.Response
+		var err error
+		if exportType, ok := exportMimeTypes[mimeType]; ok {
+			resp, err = g.srv.Files.Export(fileID, exportType).
+				Context(ctx).
+				Download()
+		} else {
+			call := g.srv.Files.Get(fileID).Context(ctx).SupportsAllDrives(true)
+			call.Header().Set("Range", fmt.Sprintf("bytes=0-%d", maxBytes-1))
+			resp, err = call.Download()
+		}
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar arquivo '%s': %w", fileID, err)
+	}
+
+	slog.Debug("conteúdo baixado", "fileID", fileID, "bytes", len(data))
+	return data, nil
+}
+
+// Thumbnail baixa a miniatura gerada pelo Drive (thumbnailLink). O link é
+// obtido na hora, porque expira em poucas horas, e pedido com até
+// thumbnailSize pixels.
+func (g *GoogleBackend) Thumbnail(ctx context.Context, fileID string, maxBytes int64) ([]byte, string, error) {
+	if g.client == nil {
+		return nil, "", ErrNoThumbnail
+	}
+
+	var f *drive.File
+	err := g.retry(ctx, func() error {
+		var err error
+		f, err = g.srv.Files.Get(fileID).
+			Context(ctx).
+			SupportsAllDrives(true).
+			Fields("thumbnailLink, hasThumbnail").
+			Do()
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao obter miniatura de '%s': %w", fileID, err)
+	}
+	if !f.HasThumbnail || f.ThumbnailLink == "" {
+		return nil, "", ErrNoThumbnail
+	}
+	link := thumbnailSizeSuffix.ReplaceAllString(f.ThumbnailLink, fmt.Sprintf("=s%d", thumbnailSize))
+
+	var data []byte
+	var mimeType string
+	err = g.retry(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return &googleapi.Error{Code: resp.StatusCode, Message: resp.Status}
+		}
+		mimeType = resp.Header.Get("Content-Type")
+		if i := strings.Index(mimeType, ";"); i >= 0 {
+			mimeType = mimeType[:i]
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao baixar miniatura de '%s': %w", fileID, err)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = "image/jpeg"
+	}
+
+	slog.Debug("miniatura baixada", "fileID", fileID, "bytes", len(data), "mimeType", mimeType)
+	return data, mimeType, nil
+}
+
+// Checksum retorna o md5Checksum informado pelo Drive.
+func (g *GoogleBackend) Checksum(ctx context.Context, fileID string) (string, error) {
+	f, err := g.GetFile(ctx, fileID)
+	if err != nil {
+		return "", err
+	}
+	return f.Md5Checksum, nil
+}
@@ -9,13 +9,24 @@ import (
 	"google.golang.org/api/drive/v3"
 )
 
-// FindFolderByName procura uma pasta pelo nome dentro de um parent.
-func FindFolderByName(ctx context.Context, srv *drive.Service, name string, parentID string) (*FileInfo, error) {
-	query := fmt.Sprintf("name = '%s' and '%s' in parents and mimeType = 'application/vnd.google-apps.folder' and trashed = false",
-		escapeDriveQuery(name), parentID)
+// FolderMimeType é o MIME type usado pelo Drive para pastas.
+const FolderMimeType = "application/vnd.google-apps.folder"
+
+// FindFolderByName procura uma pasta pelo nome dentro de um parent, primeiro
+// no cache de pastas da sessão.
+func (g *GoogleBackend) FindFolderByName(ctx context.Context, name string, parentID string) (*FileInfo, error) {
+	g.folders.warm(ctx, g.RootID(), g.listAllFolders)
+	if f, ok := g.folders.get(name, parentID); ok {
+		return f, nil
+	}
 
-	result, err := srv.Files.List().
-		Context(ctx).
+	query := fmt.Sprintf("name = '%s' and '%s' in parents and mimeType = '%s' and trashed = false",
+		escapeDriveQuery(name), parentID, FolderMimeType)
+
+	if err := g.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	result, err := g.list(ctx).
 		Q(query).
 		PageSize(1).
 		Fields("files(id, name, mimeType, parents)").
@@ -28,25 +39,25 @@ func FindFolderByName(ctx context.Context, srv *drive.Service, name string, pare
 		return nil, nil
 	}
 
-	f := result.Files[0]
-	return &FileInfo{
-		ID:       f.Id,
-		Name:     f.Name,
-		MimeType: f.MimeType,
-		Parents:  f.Parents,
-	}, nil
+	f := fileInfoFromDrive(result.Files[0])
+	g.folders.put(f)
+	return f, nil
 }
 
 // CreateFolder cria uma pasta no Drive.
-func CreateFolder(ctx context.Context, srv *drive.Service, name string, parentID string) (*FileInfo, error) {
+func (g *GoogleBackend) CreateFolder(ctx context.Context, name string, parentID string) (*FileInfo, error) {
 	folder := &drive.File{
 		Name:     name,
-		MimeType: "application/vnd.google-apps.folder",
+		MimeType: FolderMimeType,
 		Parents:  []string{parentID},
 	}
 
-	created, err := srv.Files.Create(folder).
+	if err := g.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	created, err := g.srv.Files.Create(folder).
 		Context(ctx).
+		SupportsAllDrives(true).
 		Fields("id, name, mimeType, parents").
 		Do()
 	if err != nil {
@@ -54,17 +65,14 @@ func CreateFolder(ctx context.Context, srv *drive.Service, name string, parentID
 	}
 
 	slog.Info("pasta criada", "name", name, "id", created.Id)
-	return &FileInfo{
-		ID:       created.Id,
-		Name:     created.Name,
-		MimeType: created.MimeType,
-		Parents:  created.Parents,
-	}, nil
+	f := fileInfoFromDrive(created)
+	g.folders.put(f)
+	return f, nil
 }
 
 // FindOrCreateFolder busca uma pasta pelo nome ou cria se não existir.
-func FindOrCreateFolder(ctx context.Context, srv *drive.Service, name string, parentID string) (*FileInfo, error) {
-	existing, err := FindFolderByName(ctx, srv, name, parentID)
+func FindOrCreateFolder(ctx context.Context, b Backend, name string, parentID string) (*FileInfo, error) {
+	existing, err := b.FindFolderByName(ctx, name, parentID)
 	if err != nil {
 		return nil, err
 	}
@@ -74,11 +82,13 @@ func FindOrCreateFolder(ctx context.Context, srv *drive.Service, name string, pa
 		return existing, nil
 	}
 
-	return CreateFolder(ctx, srv, name, parentID)
+	return b.CreateFolder(ctx, name, parentID)
 }
 
 // FindOrCreateNestedFolder cria (ou encontra) pastas aninhadas, ex: "backup".
-func FindOrCreateNestedFolder(ctx context.Context, srv *drive.Service, path string, rootParentID string) (*FileInfo, error) {
+// No Google Drive, as pastas já vistas na sessão são resolvidas pelo cache do
+// backend, sem chamadas à API.
+func FindOrCreateNestedFolder(ctx context.Context, b Backend, path string, rootParentID string) (*FileInfo, error) {
 	parts := strings.Split(path, "/")
 	currentParent := rootParentID
 	var lastFolder *FileInfo
@@ -89,7 +99,7 @@ func FindOrCreateNestedFolder(ctx context.Context, srv *drive.Service, path stri
 			continue
 		}
 
-		folder, err := FindOrCreateFolder(ctx, srv, part, currentParent)
+		folder, err := FindOrCreateFolder(ctx, b, part, currentParent)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao criar/encontrar pasta '%s': %w", part, err)
 		}
@@ -101,16 +111,46 @@ func FindOrCreateNestedFolder(ctx context.Context, srv *drive.Service, path stri
 	return lastFolder, nil
 }
 
+// FindNestedFolder encontra pastas aninhadas sem criá-las. Retorna nil se
+// alguma parte do caminho não existir.
+func FindNestedFolder(ctx context.Context, b Backend, path string, rootParentID string) (*FileInfo, error) {
+	currentParent := rootParentID
+	var lastFolder *FileInfo
+
+	for _, part := range strings.Split(path, "/") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		folder, err := b.FindFolderByName(ctx, part, currentParent)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao encontrar pasta '%s': %w", part, err)
+		}
+		if folder == nil {
+			return nil, nil
+		}
+
+		currentParent = folder.ID
+		lastFolder = folder
+	}
+
+	return lastFolder, nil
+}
+
 // ListFolders lista todas as pastas dentro de um parent.
-func ListFolders(ctx context.Context, srv *drive.Service, parentID string) ([]*FileInfo, error) {
+func (g *GoogleBackend) ListFolders(ctx context.Context, parentID string) ([]*FileInfo, error) {
 	var folders []*FileInfo
 	pageToken := ""
 
-	query := fmt.Sprintf("'%s' in parents and mimeType = 'application/vnd.google-apps.folder' and trashed = false", parentID)
+	query := fmt.Sprintf("'%s' in parents and mimeType = '%s' and trashed = false", parentID, FolderMimeType)
 
 	for {
-		req := srv.Files.List().
-			Context(ctx).
+		if err := g.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req := g.list(ctx).
 			Q(query).
 			PageSize(1000).
 			Fields("nextPageToken, files(id, name, mimeType, parents)").
@@ -126,12 +166,7 @@ func ListFolders(ctx context.Context, srv *drive.Service, parentID string) ([]*F
 		}
 
 		for _, f := range result.Files {
-			folders = append(folders, &FileInfo{
-				ID:       f.Id,
-				Name:     f.Name,
-				MimeType: f.MimeType,
-				Parents:  f.Parents,
-			})
+			folders = append(folders, fileInfoFromDrive(f))
 		}
 
 		pageToken = result.NextPageToken
@@ -0,0 +1,224 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// folderKey identifica uma pasta pelo nome dentro de um parent.
+type folderKey struct {
+	parentID string
+	name     string
+}
+
+// folderCache guarda, durante a sessão, as pastas já resolvidas pelo
+// GoogleBackend, para que FindOrCreateNestedFolder percorra caminhos como
+// "Trabalho/Relatórios" sem uma busca na API por segmento. Na primeira busca,
+// o cache é preenchido com uma única listagem de todas as pastas do drive.
+// É seguro para uso concorrente.
+type folderCache struct {
+	mu     sync.Mutex
+	warmed bool
+
+	// warming é fechado quando a listagem em andamento termina; nil se não
+	// houver nenhuma
+	warming chan struct{}
+	// forgotten são as pastas esquecidas durante a listagem, que não voltam
+	// ao cache com o resultado dela
+	forgotten map[string]bool
+	// gen muda a cada reset, para descartar listagens iniciadas antes dele
+	gen int
+
+	// rootID é o ID real da raiz, que aparece nos parents no lugar do alias
+	// "root" do "Meu Drive"
+	rootID  string
+	folders map[folderKey]*FileInfo
+	keys    map[string][]folderKey
+}
+
+// warm preenche o cache com as pastas de list na primeira chamada. A listagem
+// é feita sem segurar o cache, que continua atendendo get, put e forget; quem
+// chama warm enquanto ela está em andamento espera o resultado. Se a listagem
+// das pastas falhar, as buscas continuam indo à API, mas o ID da raiz, se
+// list o tiver resolvido, ainda é usado; sem ele, a próxima busca tenta de
+// novo.
+func (c *folderCache) warm(ctx context.Context, rootAlias string, list func(ctx context.Context) (rootID string, folders []*FileInfo, err error)) {
+	c.mu.Lock()
+	for c.warming != nil {
+		wait := c.warming
+		c.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return
+		}
+		c.mu.Lock()
+	}
+	if c.warmed {
+		c.mu.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	c.warming = done
+	c.forgotten = make(map[string]bool)
+	gen := c.gen
+	c.mu.Unlock()
+
+	rootID, folders, err := list(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(done)
+	c.warming = nil
+	forgotten := c.forgotten
+	c.forgotten = nil
+
+	switch {
+	case c.gen != gen:
+		// O cache foi esvaziado durante a listagem, que pode estar desatualizada
+		return
+	case err != nil && (ctx.Err() != nil || rootID == ""):
+		// Cancelada ou sem a raiz: a próxima busca tenta de novo
+		slog.Warn("erro ao listar pastas para o cache", "error", err)
+		return
+	}
+
+	c.warmed = true
+	c.init()
+	if rootID != rootAlias {
+		c.rootID = rootID
+	}
+	if err != nil {
+		slog.Warn("erro ao listar pastas para o cache, buscando uma a uma", "error", err)
+		return
+	}
+	for _, f := range folders {
+		if !forgotten[f.ID] {
+			c.putLocked(f)
+		}
+	}
+	slog.Debug("cache de pastas preenchido", "folders", len(c.folders))
+}
+
+// reset esvazia o cache, que volta a ser preenchido na próxima busca.
+func (c *folderCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.warmed = false
+	c.rootID = ""
+	c.folders = nil
+	c.keys = nil
+}
+
+func (c *folderCache) init() {
+	if c.folders == nil {
+		c.folders = make(map[folderKey]*FileInfo)
+		c.keys = make(map[string][]folderKey)
+	}
+}
+
+// key normaliza o alias da raiz para o ID real.
+func (c *folderCache) key(name, parentID string) folderKey {
+	if parentID == "root" && c.rootID != "" {
+		parentID = c.rootID
+	}
+	return folderKey{parentID: parentID, name: name}
+}
+
+// get retorna uma cópia da pasta name dentro de parentID, se estiver no cache.
+func (c *folderCache) get(name, parentID string) (*FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.folders[c.key(name, parentID)]
+	if !ok {
+		return nil, false
+	}
+	cp := *f
+	return &cp, true
+}
+
+// put registra a pasta f, encontrada ou criada.
+func (c *folderCache) put(f *FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+	c.putLocked(f)
+}
+
+func (c *folderCache) putLocked(f *FileInfo) {
+	if f == nil || !f.IsFolder() || f.Trashed {
+		return
+	}
+	for _, parent := range f.Parents {
+		k := c.key(f.Name, parent)
+		if _, ok := c.folders[k]; ok {
+			// Com duas pastas de mesmo nome, vale a primeira vista
+			continue
+		}
+		cp := *f
+		c.folders[k] = &cp
+		c.keys[f.ID] = append(c.keys[f.ID], k)
+	}
+}
+
+// forget remove o item fileID do cache, se for uma pasta conhecida. É chamado
+// quando o item é movido, renomeado, vai para a lixeira ou muda fora da sessão.
+func (c *folderCache) forget(fileID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range c.keys[fileID] {
+		delete(c.folders, k)
+	}
+	delete(c.keys, fileID)
+	if c.warming != nil {
+		c.forgotten[fileID] = true
+	}
+}
+
+// listAllFolders lista, em uma única consulta paginada, todas as pastas do
+// drive em uso, junto com o ID real da raiz. Se só a listagem falhar, o ID da
+// raiz é retornado com o erro.
+func (g *GoogleBackend) listAllFolders(ctx context.Context) (string, []*FileInfo, error) {
+	root, err := g.GetFile(ctx, g.RootID())
+	if err != nil {
+		return "", nil, err
+	}
+
+	var folders []*FileInfo
+	query := fmt.Sprintf("mimeType = '%s' and trashed = false", FolderMimeType)
+	pageToken := ""
+	for {
+		var result *drive.FileList
+		err := g.retry(ctx, func() error {
+			req := g.list(ctx).
+				Q(query).
+				PageSize(1000).
+				Fields("nextPageToken, files(id, name, mimeType, parents)")
+			if pageToken != "" {
+				req = req.PageToken(pageToken)
+			}
+			var err error
+			result, err = req.Do()
+			return err
+		})
+		if err != nil {
+			return root.ID, nil, fmt.Errorf("erro ao listar pastas: %w", err)
+		}
+
+		for _, f := range result.Files {
+			folders = append(folders, fileInfoFromDrive(f))
+		}
+
+		pageToken = result.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	return root.ID, folders, nil
+}
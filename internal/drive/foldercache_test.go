@@ -0,0 +1,140 @@
+package drive
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func folder(id, name, parentID string) *FileInfo {
+	return &FileInfo{ID: id, Name: name, MimeType: FolderMimeType, Parents: []string{parentID}}
+}
+
+// blockingList devolve uma listagem que só termina quando release é fechado.
+func blockingList(calls *atomic.Int32, started chan<- struct{}, release <-chan struct{}, folders ...*FileInfo) func(context.Context) (string, []*FileInfo, error) {
+	return func(ctx context.Context) (string, []*FileInfo, error) {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		return "raiz", folders, nil
+	}
+}
+
+func TestFolderCacheWarmDoesNotBlock(t *testing.T) {
+	var c folderCache
+	var calls atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	list := blockingList(&calls, started, release, folder("1", "Trabalho", "raiz"), folder("2", "Antiga", "raiz"))
+
+	warmed := make(chan struct{}, 2)
+	for range 2 {
+		go func() {
+			c.warm(context.Background(), "root", list)
+			warmed <- struct{}{}
+		}()
+	}
+	<-started
+
+	// Com a listagem em andamento, o cache continua respondendo
+	done := make(chan struct{})
+	go func() {
+		c.put(folder("3", "Nova", "raiz"))
+		c.forget("2")
+		c.get("Nova", "raiz")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("get/put bloqueados pela listagem")
+	}
+
+	close(release)
+	<-warmed
+	<-warmed
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("listagem feita %d vezes, want 1", n)
+	}
+	if _, ok := c.get("Trabalho", "root"); !ok {
+		t.Error("pasta da listagem fora do cache")
+	}
+	if _, ok := c.get("Nova", "raiz"); !ok {
+		t.Error("pasta registrada durante a listagem fora do cache")
+	}
+	if _, ok := c.get("Antiga", "raiz"); ok {
+		t.Error("pasta esquecida durante a listagem voltou ao cache")
+	}
+}
+
+func TestFolderCacheResetDuringWarm(t *testing.T) {
+	var c folderCache
+	var calls atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	list := blockingList(&calls, started, release, folder("1", "Trabalho", "raiz"))
+
+	warmed := make(chan struct{})
+	go func() {
+		c.warm(context.Background(), "root", list)
+		close(warmed)
+	}()
+	<-started
+	c.reset()
+	close(release)
+	<-warmed
+
+	if _, ok := c.get("Trabalho", "raiz"); ok {
+		t.Error("listagem anterior ao reset foi para o cache")
+	}
+
+	// A próxima busca lista de novo
+	c.warm(context.Background(), "root", func(ctx context.Context) (string, []*FileInfo, error) {
+		calls.Add(1)
+		return "raiz", []*FileInfo{folder("1", "Trabalho", "raiz")}, nil
+	})
+	if _, ok := c.get("Trabalho", "raiz"); !ok || calls.Load() != 2 {
+		t.Errorf("cache não foi preenchido de novo depois do reset (listagens: %d)", calls.Load())
+	}
+}
+
+func TestFolderCacheWarmErrorKeepsRootID(t *testing.T) {
+	var c folderCache
+	c.warm(context.Background(), "root", func(ctx context.Context) (string, []*FileInfo, error) {
+		return "raiz", nil, errors.New("cota excedida")
+	})
+
+	// As pastas encontradas depois, com o ID real nos parents, atendem
+	// buscas pelo alias
+	c.put(folder("1", "Trabalho", "raiz"))
+	if _, ok := c.get("Trabalho", "root"); !ok {
+		t.Error("busca pelo alias da raiz não usa o ID resolvido")
+	}
+
+	// A listagem não é repetida a cada busca
+	var calls atomic.Int32
+	c.warm(context.Background(), "root", func(ctx context.Context) (string, []*FileInfo, error) {
+		calls.Add(1)
+		return "raiz", nil, nil
+	})
+	if calls.Load() != 0 {
+		t.Error("listagem repetida depois de resolver a raiz")
+	}
+}
+
+func TestFolderCacheRetriesWithoutRootID(t *testing.T) {
+	var c folderCache
+	c.warm(context.Background(), "root", func(ctx context.Context) (string, []*FileInfo, error) {
+		return "", nil, errors.New("sem conexão")
+	})
+
+	c.warm(context.Background(), "root", func(ctx context.Context) (string, []*FileInfo, error) {
+		return "raiz", []*FileInfo{folder("1", "Trabalho", "raiz")}, nil
+	})
+	if _, ok := c.get("Trabalho", "root"); !ok {
+		t.Error("cache não foi preenchido na nova tentativa")
+	}
+}
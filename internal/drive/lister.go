@@ -2,8 +2,10 @@ package drive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -20,108 +22,142 @@ type FileInfo struct {
 	CreatedTime  string
 	ModifiedTime string
 	Size         int64
+	Md5Checksum  string
+	Trashed      bool
+
+	// Owners são os e-mails dos donos; vazio em drives compartilhados, cujos
+	// arquivos pertencem ao drive, e no backend local.
+	Owners      []string
+	WebViewLink string
+	Shared      bool
+
+	// Capabilities são as permissões do usuário sobre o item. Nil nos
+	// backends sem restrição de permissões.
+	Capabilities *Capabilities
+}
+
+// Capabilities são as permissões de um item do Drive. Em drives
+// compartilhados elas dependem do papel do usuário (leitor, colaborador...).
+type Capabilities struct {
+	CanMove   bool
+	CanRename bool
+	CanTrash  bool
 }
 
 // IsFolder retorna true se o arquivo é uma pasta.
 func (f *FileInfo) IsFolder() bool {
-	return f.MimeType == "application/vnd.google-apps.folder"
+	return f.MimeType == FolderMimeType
 }
 
-// ListAllFiles lista todos os arquivos na raiz do "Meu Drive".
-func ListAllFiles(ctx context.Context, srv *drive.Service) ([]*FileInfo, error) {
-	return listFilesInFolder(ctx, srv, "root")
+// ErrCannotMove indica um item que o usuário não tem permissão para mover,
+// comum em drives compartilhados para quem não é gerente de conteúdo.
+var ErrCannotMove = errors.New("sem permissão para mover o item")
+
+// CanMove informa se o item pode ser movido dentro do drive.
+func (f *FileInfo) CanMove() bool {
+	return f.Capabilities == nil || f.Capabilities.CanMove
 }
 
-// ListFilesInFolder lista todos os arquivos em uma pasta específica.
-func ListFilesInFolder(ctx context.Context, srv *drive.Service, folderID string) ([]*FileInfo, error) {
-	return listFilesInFolder(ctx, srv, folderID)
+// fileFields são os campos de arquivo pedidos à API em listagens e atualizações.
+const fileFields = "id, name, mimeType, parents, createdTime, modifiedTime, size, md5Checksum, trashed, " +
+	"owners(emailAddress), webViewLink, shared, " +
+	"capabilities(canMoveItemWithinDrive, canRename, canTrash)"
+
+// ListAllFiles lista todos os arquivos na raiz do backend.
+func ListAllFiles(ctx context.Context, b Backend) ([]*FileInfo, error) {
+	return ListFilesInFolder(ctx, b, b.RootID())
 }
 
-func listFilesInFolder(ctx context.Context, srv *drive.Service, folderID string) ([]*FileInfo, error) {
+// ListFilesInFolder lista todos os arquivos em uma pasta específica.
+func ListFilesInFolder(ctx context.Context, b Backend, folderID string) ([]*FileInfo, error) {
 	var allFiles []*FileInfo
 	pageToken := ""
-	query := fmt.Sprintf("'%s' in parents and trashed = false", folderID)
 
 	for {
-		var result *drive.FileList
-		
-		// Usar backoff exponencial para lidar com rate limiting e erros 500
-		operation := func() error {
-			req := srv.Files.List().
-				Context(ctx).
-				Q(query).
-				PageSize(100). // Reduzido de 1000 para 100 para evitar timeouts
-				Fields("nextPageToken, files(id, name, mimeType, parents, createdTime, modifiedTime, size)").
-				OrderBy("name")
-
-			if pageToken != "" {
-				req = req.PageToken(pageToken)
-			}
-
-			var err error
-			result, err = req.Do()
-			if err != nil {
-				// Verificar se é erro recuperável
-				if apiErr, ok := err.(*googleapi.Error); ok {
-					if apiErr.Code == 500 || apiErr.Code == 503 || apiErr.Code == 429 {
-						slog.Warn("erro temporário ao listar arquivos, tentando novamente", "code", apiErr.Code, "folder", folderID)
-						return err // Retry
-					}
-				}
-				return backoff.Permanent(fmt.Errorf("erro ao listar arquivos: %w", err))
-			}
-			return nil
-		}
-
-		// Configurar backoff
-		expBackoff := backoff.NewExponentialBackOff()
-		expBackoff.InitialInterval = 1 * time.Second
-		expBackoff.MaxInterval = 30 * time.Second
-		expBackoff.MaxElapsedTime = 2 * time.Minute
-
-		if err := backoff.Retry(operation, backoff.WithContext(expBackoff, ctx)); err != nil {
+		files, next, err := b.ListFilesPage(ctx, folderID, pageToken)
+		if err != nil {
 			return nil, err
 		}
+		allFiles = append(allFiles, files...)
 
-		for _, f := range result.Files {
-			allFiles = append(allFiles, &FileInfo{
-				ID:           f.Id,
-				Name:         f.Name,
-				MimeType:     f.MimeType,
-				Parents:      f.Parents,
-				CreatedTime:  f.CreatedTime,
-				ModifiedTime: f.ModifiedTime,
-				Size:         f.Size,
-			})
-		}
-
-		slog.Debug("arquivos listados", "count", len(result.Files), "total", len(allFiles), "folder", folderID)
+		slog.Debug("arquivos listados", "count", len(files), "total", len(allFiles), "folder", folderID)
 
-		pageToken = result.NextPageToken
+		pageToken = next
 		if pageToken == "" {
 			break
 		}
-		
-		// Pequeno delay entre páginas para evitar rate limiting
-		time.Sleep(100 * time.Millisecond)
 	}
 
 	slog.Info("total de arquivos encontrados", "count", len(allFiles), "folder", folderID)
 	return allFiles, nil
 }
 
+// ListFilesPage lista uma página dos itens de uma pasta do Drive.
+func (g *GoogleBackend) ListFilesPage(ctx context.Context, folderID string, pageToken string) ([]*FileInfo, string, error) {
+	query := fmt.Sprintf("'%s' in parents and trashed = false", folderID)
+	var result *drive.FileList
+
+	// Usar backoff exponencial para lidar com rate limiting e erros 500
+	operation := func() error {
+		if err := g.limiter.Wait(ctx); err != nil {
+			return backoff.Permanent(err)
+		}
+
+		req := g.list(ctx).
+			Q(query).
+			PageSize(100). // Reduzido de 1000 para 100 para evitar timeouts
+			Fields("nextPageToken, files(" + fileFields + ")").
+			OrderBy("name")
+
+		if pageToken != "" {
+			req = req.PageToken(pageToken)
+		}
+
+		var err error
+		result, err = req.Do()
+		if err != nil {
+			// Verificar se é erro recuperável
+			if apiErr, ok := err.(*googleapi.Error); ok {
+				if apiErr.Code == 500 || apiErr.Code == 503 || apiErr.Code == 429 {
+					slog.Warn("erro temporário ao listar arquivos, tentando novamente", "code", apiErr.Code, "folder", folderID)
+					return err // Retry
+				}
+			}
+			return backoff.Permanent(fmt.Errorf("erro ao listar arquivos: %w", err))
+		}
+		return nil
+	}
+
+	// Configurar backoff
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = 1 * time.Second
+	expBackoff.MaxInterval = 30 * time.Second
+	expBackoff.MaxElapsedTime = 2 * time.Minute
+
+	if err := backoff.Retry(operation, backoff.WithContext(expBackoff, ctx)); err != nil {
+		return nil, "", err
+	}
+
+	files := make([]*FileInfo, 0, len(result.Files))
+	for _, f := range result.Files {
+		files = append(files, fileInfoFromDrive(f))
+	}
+
+	return files, result.NextPageToken, nil
+}
+
 // ListAllFilesRecursive lista todos os arquivos recursivamente a partir de uma pasta.
-func ListAllFilesRecursive(ctx context.Context, srv *drive.Service, folderID string) ([]*FileInfo, error) {
-	return listAllFilesRecursiveWithDepth(ctx, srv, folderID, 0)
+func ListAllFilesRecursive(ctx context.Context, b Backend, folderID string) ([]*FileInfo, error) {
+	return listAllFilesRecursiveWithDepth(ctx, b, folderID, 0)
 }
 
-func listAllFilesRecursiveWithDepth(ctx context.Context, srv *drive.Service, folderID string, depth int) ([]*FileInfo, error) {
+func listAllFilesRecursiveWithDepth(ctx context.Context, b Backend, folderID string, depth int) ([]*FileInfo, error) {
 	if depth > 20 {
 		slog.Warn("profundidade máxima de recursão atingida", "depth", depth, "folder", folderID)
 		return nil, fmt.Errorf("profundidade máxima de pastas excedida (20 níveis)")
 	}
 	
-	files, err := listFilesInFolder(ctx, srv, folderID)
+	files, err := ListFilesInFolder(ctx, b, folderID)
 	if err != nil {
 		return nil, err
 	}
@@ -130,11 +166,12 @@ func listAllFilesRecursiveWithDepth(ctx context.Context, srv *drive.Service, fol
 	for _, f := range files {
 		if f.IsFolder() {
 			slog.Debug("entrando em subpasta", "folder", f.Name, "depth", depth)
-			
-			// Delay entre chamadas recursivas para evitar rate limiting
-			time.Sleep(200 * time.Millisecond)
-			
-			subFiles, err := listAllFilesRecursiveWithDepth(ctx, srv, f.ID, depth+1)
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			subFiles, err := listAllFilesRecursiveWithDepth(ctx, b, f.ID, depth+1)
 			if err != nil {
 				slog.Error("erro ao listar pasta, continuando", "folder", f.Name, "error", err)
 				// Continuar mesmo com erro em subpasta
@@ -148,3 +185,40 @@ func listAllFilesRecursiveWithDepth(ctx context.Context, srv *drive.Service, fol
 
 	return allFiles, nil
 }
+
+// WalkFiles percorre recursivamente uma pasta e chama fn para cada arquivo
+// (pastas não são passadas) com o caminho dele, formado a partir de prefix.
+func WalkFiles(ctx context.Context, b Backend, folderID string, prefix string, fn func(f *FileInfo, path string)) error {
+	return walkFilesWithDepth(ctx, b, folderID, prefix, 0, fn)
+}
+
+func walkFilesWithDepth(ctx context.Context, b Backend, folderID string, prefix string, depth int, fn func(f *FileInfo, path string)) error {
+	if depth > 20 {
+		slog.Warn("profundidade máxima de recursão atingida", "depth", depth, "folder", folderID)
+		return fmt.Errorf("profundidade máxima de pastas excedida (20 níveis)")
+	}
+
+	files, err := ListFilesInFolder(ctx, b, folderID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		p := path.Join(prefix, f.Name)
+		if !f.IsFolder() {
+			fn(f, p)
+			continue
+		}
+
+		slog.Debug("entrando em subpasta", "folder", f.Name, "depth", depth)
+
+		if err := walkFilesWithDepth(ctx, b, f.ID, p, depth+1, fn); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			slog.Error("erro ao listar pasta, continuando", "folder", f.Name, "error", err)
+		}
+	}
+
+	return nil
+}
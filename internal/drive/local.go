@@ -0,0 +1,417 @@
+package drive
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalTrashDir é o diretório, relativo à raiz, que faz o papel de lixeira no
+// LocalBackend. Ele não aparece nas listagens.
+const LocalTrashDir = ".driver-organizer-trash"
+
+// LocalBackend implementa Backend sobre uma árvore de diretórios local.
+// Os IDs são caminhos relativos à raiz, separados por "/", e a raiz é ".".
+// Como o ID depende da localização, mover ou renomear muda o ID do item;
+// as operações retornam o FileInfo atualizado.
+type LocalBackend struct {
+	root     string
+	pageSize int
+}
+
+// NewLocalBackend cria um backend para o diretório informado.
+func NewLocalBackend(root string) (*LocalBackend, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao resolver diretório '%s': %w", root, err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar diretório '%s': %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("'%s' não é um diretório", root)
+	}
+
+	slog.Info("backend local inicializado", "root", abs)
+	return &LocalBackend{root: abs, pageSize: 100}, nil
+}
+
+// Root retorna o caminho absoluto do diretório raiz.
+func (l *LocalBackend) Root() string {
+	return l.root
+}
+
+// RootID retorna o ID da raiz.
+func (l *LocalBackend) RootID() string {
+	return "."
+}
+
+// ListFilesPage lista uma página dos itens de um diretório, ordenados por nome.
+func (l *LocalBackend) ListFilesPage(ctx context.Context, folderID string, pageToken string) ([]*FileInfo, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	items, err := l.readDir(folderID, false)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao listar arquivos: %w", err)
+	}
+	return pageOf(items, pageToken, l.pageSize)
+}
+
+// ListFolders lista todos os subdiretórios de um diretório.
+func (l *LocalBackend) ListFolders(ctx context.Context, parentID string) ([]*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	folders, err := l.readDir(parentID, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar pastas: %w", err)
+	}
+	return folders, nil
+}
+
+// FindFolderByName procura um subdiretório pelo nome.
+func (l *LocalBackend) FindFolderByName(ctx context.Context, name string, parentID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, err := l.childID(parentID, name)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pasta '%s': %w", name, err)
+	}
+
+	f, err := l.stat(id)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pasta '%s': %w", name, err)
+	}
+	if !f.IsFolder() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// CreateFolder cria um subdiretório.
+func (l *LocalBackend) CreateFolder(ctx context.Context, name string, parentID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, err := l.childID(parentID, name)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar pasta '%s': %w", name, err)
+	}
+
+	if err := os.Mkdir(l.abs(id), 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar pasta '%s': %w", name, err)
+	}
+
+	slog.Info("pasta criada", "name", name, "id", id)
+	return l.stat(id)
+}
+
+// MoveFile move um item para outro diretório, mantendo o nome.
+func (l *LocalBackend) MoveFile(ctx context.Context, fileID string, newParentID string, oldParentID string) (*FileInfo, error) {
+	f, err := l.rename(ctx, fileID, newParentID, path.Base(fileID))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover arquivo '%s': %w", fileID, err)
+	}
+	slog.Debug("arquivo movido", "fileID", fileID, "newParent", newParentID)
+	return f, nil
+}
+
+// RenameFile renomeia um item dentro do mesmo diretório.
+func (l *LocalBackend) RenameFile(ctx context.Context, fileID string, newName string) (*FileInfo, error) {
+	f, err := l.rename(ctx, fileID, path.Dir(fileID), newName)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao renomear arquivo '%s': %w", fileID, err)
+	}
+	slog.Debug("arquivo renomeado", "fileID", fileID, "newName", newName)
+	return f, nil
+}
+
+// MoveAndRenameFile move e renomeia um item em uma única operação.
+func (l *LocalBackend) MoveAndRenameFile(ctx context.Context, fileID string, newName string, newParentID string, oldParentID string) (*FileInfo, error) {
+	f, err := l.rename(ctx, fileID, newParentID, newName)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover e renomear arquivo '%s': %w", fileID, err)
+	}
+	slog.Debug("arquivo movido e renomeado", "fileID", fileID, "newName", newName, "newParent", newParentID)
+	return f, nil
+}
+
+// GetFile retorna os metadados atuais de um item.
+func (l *LocalBackend) GetFile(ctx context.Context, fileID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := l.stat(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter arquivo '%s': %w", fileID, err)
+	}
+	return f, nil
+}
+
+// TrashFile remove diretórios vazios e move os demais itens para a lixeira
+// local (LocalTrashDir, na raiz), de onde podem ser recuperados manualmente.
+func (l *LocalBackend) TrashFile(ctx context.Context, fileID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, err := l.stat(fileID)
+	if err != nil {
+		return fmt.Errorf("erro ao mover para a lixeira '%s': %w", fileID, err)
+	}
+	if fileID == l.RootID() || fileID == LocalTrashDir {
+		return fmt.Errorf("erro ao mover para a lixeira '%s': item protegido", fileID)
+	}
+
+	if f.IsFolder() {
+		if err := os.Remove(l.abs(fileID)); err == nil {
+			slog.Debug("pasta vazia removida", "fileID", fileID)
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(l.abs(LocalTrashDir), 0755); err != nil {
+		return fmt.Errorf("erro ao criar lixeira local: %w", err)
+	}
+	if _, err := l.rename(ctx, fileID, LocalTrashDir, f.Name); err != nil {
+		return fmt.Errorf("erro ao mover para a lixeira '%s': %w", fileID, err)
+	}
+
+	slog.Debug("arquivo movido para a lixeira", "fileID", fileID)
+	return nil
+}
+
+// Download lê até maxBytes do conteúdo de um arquivo.
+func (l *LocalBackend) Download(ctx context.Context, fileID string, mimeType string, maxBytes int64) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := l.checkID(fileID); err != nil {
+		return nil, fmt.Errorf("erro ao baixar arquivo '%s': %w", fileID, err)
+	}
+
+	file, err := os.Open(l.abs(fileID))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar arquivo '%s': %w", fileID, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar arquivo '%s': %w", fileID, err)
+	}
+	return data, nil
+}
+
+// Checksum calcula o MD5 do arquivo lendo-o por inteiro.
+func (l *LocalBackend) Checksum(ctx context.Context, fileID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := l.checkID(fileID); err != nil {
+		return "", fmt.Errorf("erro ao calcular checksum de '%s': %w", fileID, err)
+	}
+
+	file, err := os.Open(l.abs(fileID))
+	if err != nil {
+		return "", fmt.Errorf("erro ao calcular checksum de '%s': %w", fileID, err)
+	}
+	defer file.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("erro ao calcular checksum de '%s': %w", fileID, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Thumbnail sempre retorna ErrNoThumbnail: o sistema de arquivos não gera
+// miniaturas.
+func (l *LocalBackend) Thumbnail(ctx context.Context, fileID string, maxBytes int64) ([]byte, string, error) {
+	return nil, "", ErrNoThumbnail
+}
+
+// rename move fileID para parentID/name. Diferente do Drive, um diretório não
+// pode ter dois itens com o mesmo nome, então conflitos recebem um sufixo
+// " (1)", " (2)"... antes da extensão.
+func (l *LocalBackend) rename(ctx context.Context, fileID, parentID, name string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if fileID == l.RootID() {
+		return nil, fmt.Errorf("não é possível mover a raiz")
+	}
+	if _, err := l.stat(fileID); err != nil {
+		return nil, err
+	}
+	if parentID == fileID || strings.HasPrefix(parentID, fileID+"/") {
+		return nil, fmt.Errorf("não é possível mover uma pasta para dentro dela mesma")
+	}
+
+	parent, err := l.stat(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.IsFolder() {
+		return nil, fmt.Errorf("'%s' não é uma pasta", parentID)
+	}
+
+	newID, err := l.childID(parentID, name)
+	if err != nil {
+		return nil, err
+	}
+	if newID == fileID {
+		return l.stat(fileID)
+	}
+
+	newID, err = l.freeID(parentID, name)
+	if err != nil {
+		return nil, err
+	}
+	if path.Base(newID) != name {
+		slog.Warn("nome já existe no destino, usando nome alternativo", "name", name, "new_name", path.Base(newID))
+	}
+
+	if err := os.Rename(l.abs(fileID), l.abs(newID)); err != nil {
+		return nil, err
+	}
+	return l.stat(newID)
+}
+
+// freeID retorna um ID ainda não usado dentro de parentID para o nome dado.
+func (l *LocalBackend) freeID(parentID, name string) (string, error) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := name
+	for i := 1; ; i++ {
+		id, err := l.childID(parentID, candidate)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Lstat(l.abs(id)); os.IsNotExist(err) {
+			return id, nil
+		} else if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
+
+func (l *LocalBackend) readDir(folderID string, foldersOnly bool) ([]*FileInfo, error) {
+	if err := l.checkID(folderID); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(l.abs(folderID))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*FileInfo
+	for _, e := range entries {
+		if foldersOnly && !e.IsDir() {
+			continue
+		}
+		if folderID == l.RootID() && e.Name() == LocalTrashDir {
+			continue
+		}
+		// Links simbólicos e arquivos especiais ficam de fora
+		if !e.IsDir() && !e.Type().IsRegular() {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			slog.Warn("erro ao ler item, ignorando", "name", e.Name(), "error", err)
+			continue
+		}
+		items = append(items, l.fileInfo(path.Join(folderID, e.Name()), info))
+	}
+
+	return items, nil
+}
+
+func (l *LocalBackend) stat(id string) (*FileInfo, error) {
+	if err := l.checkID(id); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(l.abs(id))
+	if err != nil {
+		return nil, err
+	}
+	return l.fileInfo(id, info), nil
+}
+
+func (l *LocalBackend) fileInfo(id string, info os.FileInfo) *FileInfo {
+	f := &FileInfo{
+		ID:   id,
+		Name: path.Base(id),
+		// O sistema de arquivos não expõe data de criação de forma portátil
+		CreatedTime:  info.ModTime().UTC().Format(time.RFC3339),
+		ModifiedTime: info.ModTime().UTC().Format(time.RFC3339),
+	}
+	if id != l.RootID() {
+		f.Parents = []string{path.Dir(id)}
+	}
+
+	if info.IsDir() {
+		f.MimeType = FolderMimeType
+		return f
+	}
+
+	f.Size = info.Size()
+	f.MimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(id)))
+	if i := strings.Index(f.MimeType, ";"); i >= 0 {
+		f.MimeType = f.MimeType[:i]
+	}
+	if f.MimeType == "" {
+		f.MimeType = "application/octet-stream"
+	}
+	return f
+}
+
+func (l *LocalBackend) childID(parentID, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("nome inválido: '%s'", name)
+	}
+	if err := l.checkID(parentID); err != nil {
+		return "", err
+	}
+	return path.Join(parentID, name), nil
+}
+
+// checkID garante que o ID é um caminho limpo dentro da raiz.
+func (l *LocalBackend) checkID(id string) error {
+	if id == "" || path.IsAbs(id) || path.Clean(id) != id || id == ".." || strings.HasPrefix(id, "../") {
+		return fmt.Errorf("ID inválido: '%s'", id)
+	}
+	return nil
+}
+
+func (l *LocalBackend) abs(id string) string {
+	return filepath.Join(l.root, filepath.FromSlash(id))
+}
@@ -0,0 +1,440 @@
+package drive
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryBackend é uma implementação de Backend totalmente em memória.
+// Modela pastas, arquivos com vários parents, itens na lixeira e paginação,
+// permitindo exercitar o fluxo de organização sem uma conta Google.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	items    map[string]*memoryItem
+	nextID   int
+	pageSize int
+}
+
+type memoryItem struct {
+	info    FileInfo
+	trashed bool
+	content []byte
+}
+
+// NewMemoryBackend cria um backend em memória vazio, contendo apenas a raiz.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		items:    make(map[string]*memoryItem),
+		pageSize: 100,
+	}
+}
+
+// SetPageSize define quantos itens ListFilesPage retorna por página.
+func (m *MemoryBackend) SetPageSize(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n > 0 {
+		m.pageSize = n
+	}
+}
+
+// RootID retorna o ID da raiz em memória.
+func (m *MemoryBackend) RootID() string {
+	return "root"
+}
+
+// AddFolder adiciona uma pasta diretamente, sem passar pelas regras de CreateFolder.
+func (m *MemoryBackend) AddFolder(name string, parents ...string) *FileInfo {
+	return m.AddFile(name, FolderMimeType, 0, parents...)
+}
+
+// AddFile adiciona um item diretamente. Sem parents, o item é criado na raiz.
+func (m *MemoryBackend) AddFile(name, mimeType string, size int64, parents ...string) *FileInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(parents) == 0 {
+		parents = []string{m.RootID()}
+	}
+	return m.insert(name, mimeType, size, parents).clone()
+}
+
+// GetFile retorna os metadados de um item, incluindo itens na lixeira.
+func (m *MemoryBackend) GetFile(ctx context.Context, fileID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[fileID]
+	if !ok {
+		return nil, fmt.Errorf("erro ao obter arquivo '%s': arquivo não encontrado", fileID)
+	}
+	f := item.info.clone()
+	f.Trashed = m.inTrash(item)
+	return f, nil
+}
+
+// TrashFile move um item para a lixeira, junto com tudo o que está dentro
+// dele. Itens na lixeira não aparecem em listagens.
+func (m *MemoryBackend) TrashFile(ctx context.Context, fileID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, err := m.lookup(fileID)
+	if err != nil {
+		return fmt.Errorf("erro ao mover para a lixeira '%s': %w", fileID, err)
+	}
+	item.trashed = true
+	return nil
+}
+
+// SetContent define o conteúdo retornado por Download para um arquivo e o
+// checksum correspondente.
+func (m *MemoryBackend) SetContent(fileID string, content []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item, ok := m.items[fileID]; ok {
+		item.content = append([]byte(nil), content...)
+		sum := md5.Sum(content)
+		item.info.Md5Checksum = hex.EncodeToString(sum[:])
+	}
+}
+
+// SetCapabilities define as permissões informadas para um item, como as de
+// um leitor de drive compartilhado.
+func (m *MemoryBackend) SetCapabilities(fileID string, c Capabilities) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item, ok := m.items[fileID]; ok {
+		item.info.Capabilities = &c
+	}
+}
+
+// Checksum retorna o checksum definido por SetContent.
+func (m *MemoryBackend) Checksum(ctx context.Context, fileID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, err := m.lookup(fileID)
+	if err != nil {
+		return "", fmt.Errorf("erro ao calcular checksum de '%s': %w", fileID, err)
+	}
+	return item.info.Md5Checksum, nil
+}
+
+// Download retorna até maxBytes do conteúdo definido com SetContent.
+func (m *MemoryBackend) Download(ctx context.Context, fileID string, mimeType string, maxBytes int64) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, err := m.lookup(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar arquivo '%s': %w", fileID, err)
+	}
+	n := min(int64(len(item.content)), max(maxBytes, 0))
+	return append([]byte(nil), item.content[:n]...), nil
+}
+
+// Thumbnail sempre retorna ErrNoThumbnail.
+func (m *MemoryBackend) Thumbnail(ctx context.Context, fileID string, maxBytes int64) ([]byte, string, error) {
+	return nil, "", ErrNoThumbnail
+}
+
+// IsTrashed informa se um item está na lixeira, diretamente ou por estar
+// dentro de uma pasta na lixeira.
+func (m *MemoryBackend) IsTrashed(fileID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[fileID]
+	return ok && m.inTrash(item)
+}
+
+// ListFilesPage lista uma página dos itens de uma pasta, ordenados por nome.
+func (m *MemoryBackend) ListFilesPage(ctx context.Context, folderID string, pageToken string) ([]*FileInfo, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return pageOf(m.children(folderID, false), pageToken, m.pageSize)
+}
+
+// ListFolders lista todas as pastas dentro de um parent.
+func (m *MemoryBackend) ListFolders(ctx context.Context, parentID string) ([]*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.children(parentID, true), nil
+}
+
+// FindFolderByName procura uma pasta pelo nome dentro de um parent.
+func (m *MemoryBackend) FindFolderByName(ctx context.Context, name string, parentID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, f := range m.children(parentID, true) {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateFolder cria uma pasta em memória.
+func (m *MemoryBackend) CreateFolder(ctx context.Context, name string, parentID string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkFolder(parentID); err != nil {
+		return nil, fmt.Errorf("erro ao criar pasta '%s': %w", name, err)
+	}
+	return m.insert(name, FolderMimeType, 0, []string{parentID}).clone(), nil
+}
+
+// MoveFile move um item de uma pasta para outra.
+func (m *MemoryBackend) MoveFile(ctx context.Context, fileID string, newParentID string, oldParentID string) (*FileInfo, error) {
+	f, err := m.update(ctx, fileID, "", newParentID, oldParentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover arquivo '%s': %w", fileID, err)
+	}
+	return f, nil
+}
+
+// RenameFile renomeia um item.
+func (m *MemoryBackend) RenameFile(ctx context.Context, fileID string, newName string) (*FileInfo, error) {
+	f, err := m.update(ctx, fileID, newName, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao renomear arquivo '%s': %w", fileID, err)
+	}
+	return f, nil
+}
+
+// MoveAndRenameFile move e renomeia um item em uma única operação.
+func (m *MemoryBackend) MoveAndRenameFile(ctx context.Context, fileID string, newName string, newParentID string, oldParentID string) (*FileInfo, error) {
+	f, err := m.update(ctx, fileID, newName, newParentID, oldParentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover e renomear arquivo '%s': %w", fileID, err)
+	}
+	return f, nil
+}
+
+// update aplica as mesmas regras de files.update do Drive: addParents e
+// removeParents são independentes e remover um parent ausente não é erro.
+func (m *MemoryBackend) update(ctx context.Context, fileID, newName, addParent, removeParent string) (*FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, err := m.lookup(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if addParent != "" {
+		if err := m.checkFolder(addParent); err != nil {
+			return nil, err
+		}
+		if addParent == fileID || m.isAncestor(fileID, addParent) {
+			return nil, fmt.Errorf("não é possível mover uma pasta para dentro dela mesma")
+		}
+	}
+
+	if removeParent != "" && removeParent != addParent {
+		parents := item.info.Parents[:0]
+		for _, p := range item.info.Parents {
+			if p != removeParent {
+				parents = append(parents, p)
+			}
+		}
+		item.info.Parents = parents
+	}
+
+	if addParent != "" && !contains(item.info.Parents, addParent) {
+		item.info.Parents = append(item.info.Parents, addParent)
+	}
+
+	if newName != "" {
+		item.info.Name = newName
+	}
+
+	item.info.ModifiedTime = time.Now().UTC().Format(time.RFC3339)
+	return item.info.clone(), nil
+}
+
+func (m *MemoryBackend) insert(name, mimeType string, size int64, parents []string) *FileInfo {
+	m.nextID++
+	now := time.Now().UTC().Format(time.RFC3339)
+	item := &memoryItem{
+		info: FileInfo{
+			ID:           fmt.Sprintf("mem-%d", m.nextID),
+			Name:         name,
+			MimeType:     mimeType,
+			Parents:      append([]string(nil), parents...),
+			CreatedTime:  now,
+			ModifiedTime: now,
+			Size:         size,
+		},
+	}
+	m.items[item.info.ID] = item
+	return &item.info
+}
+
+func (m *MemoryBackend) lookup(fileID string) (*memoryItem, error) {
+	item, ok := m.items[fileID]
+	if !ok {
+		return nil, fmt.Errorf("arquivo não encontrado: %s", fileID)
+	}
+	if m.inTrash(item) {
+		return nil, fmt.Errorf("arquivo está na lixeira: %s", fileID)
+	}
+	return item, nil
+}
+
+// inTrash informa se o item está na lixeira: descartado diretamente ou dentro
+// de uma pasta descartada, como no Drive.
+func (m *MemoryBackend) inTrash(item *memoryItem) bool {
+	if item.trashed {
+		return true
+	}
+	seen := make(map[string]bool)
+	queue := append([]string(nil), item.info.Parents...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		parent, ok := m.items[id]
+		if !ok {
+			continue
+		}
+		if parent.trashed {
+			return true
+		}
+		queue = append(queue, parent.info.Parents...)
+	}
+	return false
+}
+
+func (m *MemoryBackend) checkFolder(folderID string) error {
+	if folderID == m.RootID() {
+		return nil
+	}
+	item, err := m.lookup(folderID)
+	if err != nil {
+		return err
+	}
+	if !item.info.IsFolder() {
+		return fmt.Errorf("'%s' não é uma pasta", folderID)
+	}
+	return nil
+}
+
+// isAncestor informa se ancestorID aparece em algum caminho de folderID até a raiz.
+func (m *MemoryBackend) isAncestor(ancestorID, folderID string) bool {
+	seen := make(map[string]bool)
+	queue := []string{folderID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		item, ok := m.items[id]
+		if !ok {
+			continue
+		}
+		for _, p := range item.info.Parents {
+			if p == ancestorID {
+				return true
+			}
+			queue = append(queue, p)
+		}
+	}
+	return false
+}
+
+func (m *MemoryBackend) children(parentID string, foldersOnly bool) []*FileInfo {
+	var result []*FileInfo
+	for _, item := range m.items {
+		if !contains(item.info.Parents, parentID) || (foldersOnly && !item.info.IsFolder()) {
+			continue
+		}
+		if m.inTrash(item) {
+			continue
+		}
+		result = append(result, item.info.clone())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func (f *FileInfo) clone() *FileInfo {
+	c := *f
+	c.Parents = append([]string(nil), f.Parents...)
+	c.Owners = append([]string(nil), f.Owners...)
+	if f.Capabilities != nil {
+		caps := *f.Capabilities
+		c.Capabilities = &caps
+	}
+	return &c
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
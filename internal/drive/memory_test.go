@@ -0,0 +1,111 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryBackendTrashPropagatesToDescendants(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend()
+
+	folder := m.AddFolder("Projetos")
+	sub := m.AddFolder("Antigos", folder.ID)
+	file := m.AddFile("notas.txt", "text/plain", 10, sub.ID)
+	shared := m.AddFile("compartilhado.txt", "text/plain", 10, sub.ID, m.RootID())
+
+	if err := m.TrashFile(ctx, folder.ID); err != nil {
+		t.Fatalf("TrashFile: %v", err)
+	}
+
+	for _, id := range []string{folder.ID, sub.ID, file.ID} {
+		if !m.IsTrashed(id) {
+			t.Errorf("IsTrashed(%s) = false, want true", id)
+		}
+		f, err := m.GetFile(ctx, id)
+		if err != nil {
+			t.Fatalf("GetFile(%s): %v", id, err)
+		}
+		if !f.Trashed {
+			t.Errorf("GetFile(%s).Trashed = false, want true", id)
+		}
+	}
+	if _, err := m.MoveFile(ctx, file.ID, m.RootID(), sub.ID); err == nil {
+		t.Error("MoveFile de item dentro da lixeira deveria falhar")
+	}
+
+	files, err := ListAllFiles(ctx, m)
+	if err != nil {
+		t.Fatalf("ListAllFiles: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("raiz lista %d itens, want 0", len(files))
+	}
+
+	var walked []string
+	if err := WalkFiles(ctx, m, m.RootID(), "", func(f *FileInfo, p string) { walked = append(walked, p) }); err != nil {
+		t.Fatalf("WalkFiles: %v", err)
+	}
+	if len(walked) != 0 {
+		t.Errorf("WalkFiles percorreu %v, want nada", walked)
+	}
+
+	// Com a raiz como segundo parent, o item continua lá, mas também está
+	// dentro de uma pasta descartada
+	if !m.IsTrashed(shared.ID) {
+		t.Errorf("IsTrashed(%s) = false, want true", shared.ID)
+	}
+}
+
+func TestMemoryBackendPagination(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend()
+	m.SetPageSize(3)
+
+	folder := m.AddFolder("Fotos")
+	for i := range 7 {
+		m.AddFile(fmt.Sprintf("IMG_%02d.jpg", i), "image/jpeg", 100, folder.ID)
+	}
+
+	files, err := ListFilesInFolder(ctx, m, folder.ID)
+	if err != nil {
+		t.Fatalf("ListFilesInFolder: %v", err)
+	}
+	if len(files) != 7 {
+		t.Fatalf("ListFilesInFolder retornou %d arquivos, want 7", len(files))
+	}
+	if files[0].Name != "IMG_00.jpg" || files[6].Name != "IMG_06.jpg" {
+		t.Errorf("ordem inesperada: %s ... %s", files[0].Name, files[6].Name)
+	}
+}
+
+func TestFindOrCreateNestedFolderReusesExisting(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend()
+
+	first, err := FindOrCreateNestedFolder(ctx, m, "Financeiro/Boletos", m.RootID())
+	if err != nil {
+		t.Fatalf("FindOrCreateNestedFolder: %v", err)
+	}
+	second, err := FindOrCreateNestedFolder(ctx, m, "Financeiro/Boletos", m.RootID())
+	if err != nil {
+		t.Fatalf("FindOrCreateNestedFolder: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Errorf("segunda chamada criou outra pasta: %s != %s", second.ID, first.ID)
+	}
+
+	found, err := FindNestedFolder(ctx, m, "Financeiro/Boletos", m.RootID())
+	if err != nil {
+		t.Fatalf("FindNestedFolder: %v", err)
+	}
+	if found == nil || found.ID != first.ID {
+		t.Errorf("FindNestedFolder = %v, want %s", found, first.ID)
+	}
+
+	parent, _ := FindNestedFolder(ctx, m, "Financeiro", m.RootID())
+	if _, err := m.MoveFile(ctx, parent.ID, first.ID, m.RootID()); err == nil {
+		t.Error("mover uma pasta para dentro de uma subpasta dela deveria falhar")
+	}
+}
@@ -13,111 +13,118 @@ import (
 )
 
 // MoveFile move um arquivo de uma pasta para outra.
-func MoveFile(ctx context.Context, srv *drive.Service, fileID string, newParentID string, oldParentID string) error {
-	operation := func() error {
-		_, err := srv.Files.Update(fileID, nil).
+func (g *GoogleBackend) MoveFile(ctx context.Context, fileID string, newParentID string, oldParentID string) (*FileInfo, error) {
+	var updated *drive.File
+	g.folders.forget(fileID)
+	err := g.retry(ctx, func() error {
+		var err error
+		updated, err = g.srv.Files.Update(fileID, nil).
 			Context(ctx).
+			SupportsAllDrives(true).
 			AddParents(newParentID).
 			RemoveParents(oldParentID).
-			Fields("id, parents").
+			Fields(fileFields).
 			Do()
-		if err != nil {
-			if isRetryable(err) {
-				return err // retryable
-			}
-			return backoff.Permanent(err) // não retryable
-		}
-		return nil
-	}
-
-	b := backoff.NewExponentialBackOff()
-	b.MaxElapsedTime = 2 * time.Minute
-	b.InitialInterval = 1 * time.Second
-	b.MaxInterval = 30 * time.Second
-
-	if err := backoff.Retry(operation, backoff.WithContext(b, ctx)); err != nil {
-		return fmt.Errorf("erro ao mover arquivo '%s': %w", fileID, err)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover arquivo '%s': %w", fileID, err)
 	}
 
 	slog.Debug("arquivo movido", "fileID", fileID, "newParent", newParentID)
-	return nil
+	return fileInfoFromDrive(updated), nil
 }
 
 // RenameFile renomeia um arquivo.
-func RenameFile(ctx context.Context, srv *drive.Service, fileID string, newName string) error {
-	operation := func() error {
-		file := &drive.File{
-			Name: newName,
-		}
-		_, err := srv.Files.Update(fileID, file).
+func (g *GoogleBackend) RenameFile(ctx context.Context, fileID string, newName string) (*FileInfo, error) {
+	var updated *drive.File
+	g.folders.forget(fileID)
+	err := g.retry(ctx, func() error {
+		var err error
+		updated, err = g.srv.Files.Update(fileID, &drive.File{Name: newName}).
 			Context(ctx).
-			Fields("id, name").
+			SupportsAllDrives(true).
+			Fields(fileFields).
 			Do()
-		if err != nil {
-			if isRetryable(err) {
-				return err // retryable
-			}
-			return backoff.Permanent(err) // não retryable
-		}
-		return nil
-	}
-
-	b := backoff.NewExponentialBackOff()
-	b.MaxElapsedTime = 2 * time.Minute
-	b.InitialInterval = 1 * time.Second
-	b.MaxInterval = 30 * time.Second
-
-	if err := backoff.Retry(operation, backoff.WithContext(b, ctx)); err != nil {
-		return fmt.Errorf("erro ao renomear arquivo '%s': %w", fileID, err)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao renomear arquivo '%s': %w", fileID, err)
 	}
 
 	slog.Debug("arquivo renomeado", "fileID", fileID, "newName", newName)
-	return nil
+	return fileInfoFromDrive(updated), nil
 }
 
 // MoveAndRenameFile move e renomeia um arquivo em uma única operação.
-func MoveAndRenameFile(ctx context.Context, srv *drive.Service, fileID string, newName string, newParentID string, oldParentID string) error {
-	operation := func() error {
-		file := &drive.File{
-			Name: newName,
-		}
-		_, err := srv.Files.Update(fileID, file).
+func (g *GoogleBackend) MoveAndRenameFile(ctx context.Context, fileID string, newName string, newParentID string, oldParentID string) (*FileInfo, error) {
+	var updated *drive.File
+	g.folders.forget(fileID)
+	err := g.retry(ctx, func() error {
+		var err error
+		updated, err = g.srv.Files.Update(fileID, &drive.File{Name: newName}).
 			Context(ctx).
+			SupportsAllDrives(true).
 			AddParents(newParentID).
 			RemoveParents(oldParentID).
-			Fields("id, name, parents").
+			Fields(fileFields).
 			Do()
-		if err != nil {
-			if isRetryable(err) {
-				return err // retryable
-			}
-			return backoff.Permanent(err) // não retryable
-		}
-		return nil
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover e renomear arquivo '%s': %w", fileID, err)
 	}
 
-	b := backoff.NewExponentialBackOff()
-	b.MaxElapsedTime = 2 * time.Minute
-	b.InitialInterval = 1 * time.Second
-	b.MaxInterval = 30 * time.Second
+	slog.Debug("arquivo movido e renomeado", "fileID", fileID, "newName", newName, "newParent", newParentID)
+	return fileInfoFromDrive(updated), nil
+}
 
-	if err := backoff.Retry(operation, backoff.WithContext(b, ctx)); err != nil {
-		return fmt.Errorf("erro ao mover e renomear arquivo '%s': %w", fileID, err)
+// GetFile retorna os metadados atuais de um arquivo.
+func (g *GoogleBackend) GetFile(ctx context.Context, fileID string) (*FileInfo, error) {
+	var f *drive.File
+	err := g.retry(ctx, func() error {
+		var err error
+		f, err = g.srv.Files.Get(fileID).
+			Context(ctx).
+			SupportsAllDrives(true).
+			Fields(fileFields).
+			Do()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter arquivo '%s': %w", fileID, err)
 	}
+	return fileInfoFromDrive(f), nil
+}
 
-	slog.Debug("arquivo movido e renomeado", "fileID", fileID, "newName", newName, "newParent", newParentID)
+// TrashFile move um arquivo para a lixeira do Drive.
+func (g *GoogleBackend) TrashFile(ctx context.Context, fileID string) error {
+	g.folders.forget(fileID)
+	err := g.retry(ctx, func() error {
+		_, err := g.srv.Files.Update(fileID, &drive.File{Trashed: true}).
+			Context(ctx).
+			SupportsAllDrives(true).
+			Fields("id, trashed").
+			Do()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao mover para a lixeira '%s': %w", fileID, err)
+	}
+
+	slog.Debug("arquivo movido para a lixeira", "fileID", fileID)
 	return nil
 }
 
 // MoveFilesToFolder move vários arquivos para uma pasta destino.
-func MoveFilesToFolder(ctx context.Context, srv *drive.Service, files []*FileInfo, destFolderID string) (moved int, errors []error) {
+func MoveFilesToFolder(ctx context.Context, b Backend, files []*FileInfo, destFolderID string) (moved int, errors []error) {
 	for _, f := range files {
-		oldParent := "root"
+		oldParent := b.RootID()
 		if len(f.Parents) > 0 {
 			oldParent = f.Parents[0]
 		}
 
-		if err := MoveFile(ctx, srv, f.ID, destFolderID, oldParent); err != nil {
+		if _, err := b.MoveFile(ctx, f.ID, destFolderID, oldParent); err != nil {
 			errors = append(errors, fmt.Errorf("'%s': %w", f.Name, err))
 			slog.Error("falha ao mover arquivo", "name", f.Name, "error", err)
 			continue
@@ -129,6 +136,31 @@ func MoveFilesToFolder(ctx context.Context, srv *drive.Service, files []*FileInf
 	return moved, errors
 }
 
+// retry executa call com backoff exponencial, repetindo apenas erros
+// retryable. Cada tentativa espera uma permissão do
+// limitador.
+func (g *GoogleBackend) retry(ctx context.Context, call func() error) error {
+	operation := func() error {
+		if err := g.limiter.Wait(ctx); err != nil {
+			return backoff.Permanent(err)
+		}
+		if err := call(); err != nil {
+			if isRetryable(err) {
+				return err
+			}
+			return backoff.Permanent(err)
+		}
+		return nil
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 2 * time.Minute
+	b.InitialInterval = 1 * time.Second
+	b.MaxInterval = 30 * time.Second
+
+	return backoff.Retry(operation, backoff.WithContext(b, ctx))
+}
+
 // isRetryable verifica se um erro da API Google é retryable.
 func isRetryable(err error) bool {
 	if apiErr, ok := err.(*googleapi.Error); ok {
@@ -0,0 +1,276 @@
+package export
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// Formatos de saída suportados.
+const (
+	FormatCSV    = "csv"
+	FormatJSONL  = "jsonl"
+	FormatSQLite = "sqlite"
+)
+
+// Record é uma linha do inventário: os metadados do arquivo e o caminho dele
+// a partir da pasta exportada.
+type Record struct {
+	ID           string   `json:"id"`
+	Path         string   `json:"path"`
+	Name         string   `json:"name"`
+	MimeType     string   `json:"mime_type"`
+	Size         int64    `json:"size"`
+	Md5Checksum  string   `json:"md5_checksum,omitempty"`
+	CreatedTime  string   `json:"created_time,omitempty"`
+	ModifiedTime string   `json:"modified_time,omitempty"`
+	Owners       []string `json:"owners,omitempty"`
+	Shared       bool     `json:"shared"`
+	WebViewLink  string   `json:"web_view_link,omitempty"`
+	Parents      []string `json:"parents,omitempty"`
+}
+
+// NewRecord monta o registro de f, encontrado em path.
+func NewRecord(f *drive.FileInfo, path string) Record {
+	return Record{
+		ID:           f.ID,
+		Path:         path,
+		Name:         f.Name,
+		MimeType:     f.MimeType,
+		Size:         f.Size,
+		Md5Checksum:  f.Md5Checksum,
+		CreatedTime:  f.CreatedTime,
+		ModifiedTime: f.ModifiedTime,
+		Owners:       f.Owners,
+		Shared:       f.Shared,
+		WebViewLink:  f.WebViewLink,
+		Parents:      f.Parents,
+	}
+}
+
+// columns são as colunas do CSV e da tabela SQLite, na ordem de values.
+var columns = []string{
+	"id", "path", "name", "mime_type", "size", "md5_checksum", "created_time",
+	"modified_time", "owners", "shared", "web_view_link", "parents",
+}
+
+// listSep separa os itens de owners e parents no CSV e no SQLite.
+const listSep = ";"
+
+func (r Record) values() []string {
+	return []string{
+		r.ID, r.Path, r.Name, r.MimeType, strconv.FormatInt(r.Size, 10), r.Md5Checksum,
+		r.CreatedTime, r.ModifiedTime, strings.Join(r.Owners, listSep),
+		strconv.FormatBool(r.Shared), r.WebViewLink, strings.Join(r.Parents, listSep),
+	}
+}
+
+// Writer grava registros um a um, sem manter o inventário em memória.
+type Writer interface {
+	Write(r Record) error
+	// Close conclui a gravação; o arquivo só fica completo depois dele.
+	Close() error
+}
+
+// FormatFromPath deduz o formato pela extensão de path, ou "" se ela não for
+// reconhecida.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".db", ".sqlite", ".sqlite3":
+		return FormatSQLite
+	}
+	return ""
+}
+
+// Create abre path para gravar no formato indicado, substituindo o arquivo se
+// ele existir.
+func Create(path, format string) (Writer, error) {
+	switch format {
+	case FormatCSV, FormatJSONL:
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar arquivo de exportação: %w", err)
+		}
+		return newStreamWriter(f, format)
+
+	case FormatSQLite:
+		return newSQLiteWriter(path)
+
+	default:
+		return nil, unknownFormat(format)
+	}
+}
+
+// New grava em w, que não é fechado por Close. Só CSV e JSON Lines podem ser
+// gravados em um fluxo.
+func New(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV, FormatJSONL:
+		return newStreamWriter(nopCloser{w}, format)
+	case FormatSQLite:
+		return nil, fmt.Errorf("o formato sqlite precisa de um arquivo de saída")
+	default:
+		return nil, unknownFormat(format)
+	}
+}
+
+func newStreamWriter(out io.WriteCloser, format string) (Writer, error) {
+	if format == FormatCSV {
+		return newCSVWriter(out)
+	}
+	return newJSONLWriter(out), nil
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("formato desconhecido '%s' (use %s, %s ou %s)", format, FormatCSV, FormatJSONL, FormatSQLite)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+type csvWriter struct {
+	out io.WriteCloser
+	w   *csv.Writer
+}
+
+func newCSVWriter(out io.WriteCloser) (*csvWriter, error) {
+	w := csv.NewWriter(out)
+	if err := w.Write(columns); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return &csvWriter{out: out, w: w}, nil
+}
+
+func (c *csvWriter) Write(r Record) error {
+	if err := c.w.Write(r.values()); err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	err := c.w.Error()
+	if cerr := c.out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar CSV: %w", err)
+	}
+	return nil
+}
+
+type jsonlWriter struct {
+	out io.WriteCloser
+	enc *json.Encoder
+}
+
+func newJSONLWriter(out io.WriteCloser) *jsonlWriter {
+	return &jsonlWriter{out: out, enc: json.NewEncoder(out)}
+}
+
+func (j *jsonlWriter) Write(r Record) error {
+	if err := j.enc.Encode(r); err != nil {
+		return fmt.Errorf("erro ao gravar JSON Lines: %w", err)
+	}
+	return nil
+}
+
+func (j *jsonlWriter) Close() error {
+	if err := j.out.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar JSON Lines: %w", err)
+	}
+	return nil
+}
+
+const createTable = `CREATE TABLE files (
+	id            TEXT PRIMARY KEY,
+	path          TEXT NOT NULL,
+	name          TEXT NOT NULL,
+	mime_type     TEXT,
+	size          INTEGER,
+	md5_checksum  TEXT,
+	created_time  TEXT,
+	modified_time TEXT,
+	owners        TEXT,
+	shared        INTEGER,
+	web_view_link TEXT,
+	parents       TEXT
+)`
+
+// sqliteWriter grava tudo em uma única transação, confirmada em Close.
+type sqliteWriter struct {
+	db   *sql.DB
+	tx   *sql.Tx
+	stmt *sql.Stmt
+}
+
+func newSQLiteWriter(path string) (*sqliteWriter, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("erro ao substituir '%s': %w", path, err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir banco SQLite: %w", err)
+	}
+
+	s := &sqliteWriter{db: db}
+	if err := s.init(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erro ao criar banco SQLite: %w", err)
+	}
+	return s, nil
+}
+
+func (s *sqliteWriter) init() error {
+	if _, err := s.db.Exec(createTable); err != nil {
+		return err
+	}
+	var err error
+	if s.tx, err = s.db.Begin(); err != nil {
+		return err
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	s.stmt, err = s.tx.Prepare("INSERT OR REPLACE INTO files (" + strings.Join(columns, ", ") + ") VALUES (" + placeholders + ")")
+	return err
+}
+
+func (s *sqliteWriter) Write(r Record) error {
+	_, err := s.stmt.Exec(r.ID, r.Path, r.Name, r.MimeType, r.Size, r.Md5Checksum, r.CreatedTime,
+		r.ModifiedTime, strings.Join(r.Owners, listSep), r.Shared, r.WebViewLink, strings.Join(r.Parents, listSep))
+	if err != nil {
+		return fmt.Errorf("erro ao gravar no SQLite: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteWriter) Close() error {
+	s.stmt.Close()
+	err := s.tx.Commit()
+	if err == nil {
+		_, err = s.db.Exec("CREATE INDEX files_path ON files (path); CREATE INDEX files_md5 ON files (md5_checksum)")
+	}
+	if cerr := s.db.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar no SQLite: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package extract
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrUnsupported indica um tipo de arquivo sem extrator de texto.
+	ErrUnsupported = errors.New("tipo de arquivo sem extração de texto")
+
+	// ErrNoText indica que o arquivo foi lido, mas não tem texto.
+	ErrNoText = errors.New("arquivo sem texto")
+
+	// ErrScanned indica um PDF digitalizado: só imagens, sem camada de texto.
+	ErrScanned = errors.New("PDF digitalizado, sem camada de texto")
+
+	// ErrEncrypted indica um documento protegido por senha ou criptografado.
+	ErrEncrypted = errors.New("documento criptografado")
+)
+
+// Supported informa se há extrator de texto para o tipo.
+func Supported(mimeType string) bool {
+	switch mimeType {
+	case "application/pdf", MimeDocx, MimeXlsx, MimePptx:
+		return true
+	}
+	return IsPlainText(mimeType)
+}
+
+// IsPlainText informa se o tipo pode ser lido diretamente como texto. Para
+// esses tipos basta o começo do arquivo; os demais precisam do arquivo inteiro.
+func IsPlainText(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-yaml", "application/yaml", "application/x-sh",
+		"application/sql", "application/rtf":
+		return true
+	}
+	return false
+}
+
+// Text extrai o texto de data conforme o tipo do arquivo. Em PDFs, lê no
+// máximo as primeiras maxPages páginas.
+func Text(mimeType string, data []byte, maxPages int) (string, error) {
+	var text string
+	var err error
+	switch mimeType {
+	case "application/pdf":
+		text, err = PDFText(data, maxPages)
+	case MimeDocx:
+		text, err = DocxText(data)
+	case MimeXlsx:
+		text, err = XlsxText(data)
+	case MimePptx:
+		text, err = PptxText(data)
+	default:
+		if !IsPlainText(mimeType) {
+			return "", ErrUnsupported
+		}
+		// O corte do download pode partir um caractere multibyte no meio
+		text = strings.ToValidUTF8(string(data), "")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
@@ -0,0 +1,78 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Decodificadores registrados para image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// maxImagePixels evita decodificar imagens gigantes (ex: panoramas), que
+// ocupariam centenas de MB na memória.
+const maxImagePixels = 50_000_000
+
+// Downscale reduz a imagem para que o lado maior tenha no máximo maxDim
+// pixels e a recodifica como JPEG. Aceita JPEG, PNG e GIF; outros formatos
+// retornam ErrUnsupported.
+func Downscale(data []byte, maxDim int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("imagem grande demais (%dx%d)", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar imagem: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resize(src, maxDim), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("erro ao codificar imagem: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resize reduz src pela média de cada bloco de pixels de origem. Imagens que
+// já cabem em maxDim são mantidas.
+func resize(src image.Image, maxDim int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxDim && h <= maxDim {
+		return src
+	}
+
+	dw, dh := maxDim, h*maxDim/w
+	if h > w {
+		dw, dh = w*maxDim/h, maxDim
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*h/dh, b.Min.Y+(y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*w/dw, b.Min.X+(x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
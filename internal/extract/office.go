@@ -0,0 +1,439 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Tipos MIME dos formatos Office Open XML.
+const (
+	MimeDocx = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MimeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MimePptx = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+)
+
+const (
+	// sheetRows é quantas linhas de cada planilha entram no texto.
+	sheetRows = 5
+
+	// maxSheets e maxSlides limitam as planilhas e slides lidos.
+	maxSheets = 20
+	maxSlides = 100
+)
+
+// oleMagic é a assinatura dos arquivos OLE. Um docx/xlsx/pptx nesse formato
+// foi protegido por senha (o Office criptografa o pacote zip inteiro).
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0}
+
+func openOffice(data []byte) (*zip.Reader, error) {
+	if bytes.HasPrefix(data, oleMagic) {
+		return nil, ErrEncrypted
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo Office: %w", err)
+	}
+	return zr, nil
+}
+
+// readPart lê uma parte do pacote. Retorna nil se ela não existir.
+func readPart(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler '%s': %w", name, err)
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxDecodedStream))
+	}
+	return nil, nil
+}
+
+// relationships lê o arquivo de relacionamentos de uma parte e retorna o
+// caminho no pacote de cada alvo, pelo ID.
+func relationships(zr *zip.Reader, part string) (map[string]string, error) {
+	dir, file := path.Split(part)
+	data, err := readPart(zr, dir+"_rels/"+file+".rels")
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("erro ao ler relacionamentos de '%s': %w", part, err)
+	}
+
+	targets := make(map[string]string, len(rels.Items))
+	for _, r := range rels.Items {
+		if strings.HasPrefix(r.Target, "/") {
+			targets[r.ID] = strings.TrimPrefix(r.Target, "/")
+		} else {
+			targets[r.ID] = path.Join(dir, r.Target)
+		}
+	}
+	return targets, nil
+}
+
+// relID retorna o atributo r:id de um elemento.
+func relID(se xml.StartElement) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == "id" && strings.Contains(a.Name.Space, "relationships") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// DocxText extrai os parágrafos de um documento do Word.
+func DocxText(data []byte) (string, error) {
+	zr, err := openOffice(data)
+	if err != nil {
+		return "", err
+	}
+	doc, err := readPart(zr, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	if doc == nil {
+		return "", errors.New("documento do Word sem word/document.xml")
+	}
+
+	var sb strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	inText := false
+	for sb.Len() < maxTextBytes {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler documento do Word: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// XlsxText extrai o nome de cada planilha e as primeiras linhas dela.
+func XlsxText(data []byte) (string, error) {
+	zr, err := openOffice(data)
+	if err != nil {
+		return "", err
+	}
+
+	workbook, err := readPart(zr, "xl/workbook.xml")
+	if err != nil {
+		return "", err
+	}
+	if workbook == nil {
+		return "", errors.New("planilha do Excel sem xl/workbook.xml")
+	}
+	rels, err := relationships(zr, "xl/workbook.xml")
+	if err != nil {
+		return "", err
+	}
+	shared, err := sharedStrings(zr)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(workbook))
+	sheets := 0
+	for sheets < maxSheets {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler planilha do Excel: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "sheet" {
+			continue
+		}
+		sheets++
+
+		fmt.Fprintf(&sb, "Planilha: %s\n", attr(se, "name"))
+		part, ok := rels[relID(se)]
+		if !ok {
+			continue
+		}
+		rows, err := sheetRowsText(zr, part, shared)
+		if err != nil {
+			return "", err
+		}
+		for _, row := range rows {
+			sb.WriteString(strings.Join(row, " | "))
+			sb.WriteByte('\n')
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
+
+// sharedStrings lê a tabela de textos compartilhados das células.
+func sharedStrings(zr *zip.Reader) ([]string, error) {
+	data, err := readPart(zr, "xl/sharedStrings.xml")
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var result []string
+	var cur strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inText, inPhonetic := false, false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler textos da planilha: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				cur.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				// Guia fonético (japonês), não faz parte do texto
+				inPhonetic = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				result = append(result, cur.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		case xml.CharData:
+			if inText && !inPhonetic {
+				cur.Write(t)
+			}
+		}
+	}
+}
+
+// sheetRowsText lê as primeiras linhas não vazias de uma planilha.
+func sheetRowsText(zr *zip.Reader, part string, shared []string) ([][]string, error) {
+	data, err := readPart(zr, part)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	var row []string
+	var cellType string
+	var value strings.Builder
+	inValue := false
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for len(rows) < sheetRows {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler '%s': %w", part, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+			case "c":
+				cellType = attr(t, "t")
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				text := value.String()
+				if cellType == "s" {
+					if i, err := strconv.Atoi(text); err == nil && i >= 0 && i < len(shared) {
+						text = shared[i]
+					}
+				}
+				if text = strings.TrimSpace(text); text != "" {
+					row = append(row, text)
+				}
+			case "row":
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// PptxText extrai o título de cada slide de uma apresentação.
+func PptxText(data []byte) (string, error) {
+	zr, err := openOffice(data)
+	if err != nil {
+		return "", err
+	}
+
+	presentation, err := readPart(zr, "ppt/presentation.xml")
+	if err != nil {
+		return "", err
+	}
+	if presentation == nil {
+		return "", errors.New("apresentação do PowerPoint sem ppt/presentation.xml")
+	}
+	rels, err := relationships(zr, "ppt/presentation.xml")
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(presentation))
+	slides := 0
+	for slides < maxSlides {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler apresentação do PowerPoint: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "sldId" {
+			continue
+		}
+		slides++
+
+		part, ok := rels[relID(se)]
+		if !ok {
+			continue
+		}
+		title, err := slideTitle(zr, part)
+		if err != nil {
+			return "", err
+		}
+		if title != "" {
+			fmt.Fprintf(&sb, "Slide %d: %s\n", slides, title)
+		}
+	}
+	return sb.String(), nil
+}
+
+// slideTitle retorna o texto do placeholder de título de um slide.
+func slideTitle(zr *zip.Reader, part string) (string, error) {
+	data, err := readPart(zr, part)
+	if err != nil || data == nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0 // aninhamento dentro de um <p:sp>
+	isTitle, inText := false, false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler '%s': %w", part, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "sp" {
+				if depth == 0 {
+					text.Reset()
+					isTitle = false
+				}
+				depth++
+			}
+			switch t.Name.Local {
+			case "ph":
+				if typ := attr(t, "type"); typ == "title" || typ == "ctrTitle" {
+					isTitle = true
+				}
+			case "t":
+				inText = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if depth > 0 {
+					text.WriteByte(' ')
+				}
+			case "sp":
+				depth--
+				if depth == 0 && isTitle {
+					return strings.Join(strings.Fields(text.String()), " "), nil
+				}
+			}
+		case xml.CharData:
+			if inText && depth > 0 {
+				text.Write(t)
+			}
+		}
+	}
+}
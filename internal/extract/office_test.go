@@ -0,0 +1,49 @@
+package extract
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOfficeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		mimeType string
+		want     string
+		err      error
+	}{
+		{"docx", "doc.docx", MimeDocx, "Contrato de locação\nLocador:\tMaria", nil},
+		{"xlsx", "sheet.xlsx", MimeXlsx,
+			"Planilha: Gastos\nMês | Valor\nJaneiro | 150.5\nTotal | 150.5\n5\n6\n\nPlanilha: Resumo\nok", nil},
+		{"pptx", "slides.pptx", MimePptx, "Slide 1: Resultados 2024\nSlide 3: Próximos passos", nil},
+		{"protegido por senha", "protected.docx", MimeDocx, "", ErrEncrypted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Text(tt.mimeType, readFixture(t, tt.file), 0)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Text = %q, %v; want erro %v", got, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextPlainAndUnsupported(t *testing.T) {
+	if got, err := Text("text/plain", []byte("  nota\xc3"), 0); err != nil || got != "nota" {
+		t.Errorf("Text(text/plain) = %q, %v; want nota", got, err)
+	}
+	if _, err := Text("text/plain", []byte(" \n "), 0); !errors.Is(err, ErrNoText) {
+		t.Errorf("Text de arquivo em branco = %v, want ErrNoText", err)
+	}
+	if _, err := Text("image/png", []byte("png"), 0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Text(image/png) = %v, want ErrUnsupported", err)
+	}
+	if _, err := Text(MimeDocx, []byte("não é zip"), 0); err == nil {
+		t.Error("Text de um docx inválido deveria falhar")
+	}
+}
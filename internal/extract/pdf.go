@@ -0,0 +1,587 @@
+package extract
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxDecodedStream limita o tamanho de um stream descompactado, para que
+	// um arquivo malicioso não esgote a memória.
+	maxDecodedStream = 32 << 20
+
+	// maxTextBytes limita o texto extraído; o prompt usa só o começo.
+	maxTextBytes = 256 << 10
+
+	// minScannedText é o mínimo de letras e dígitos para que um PDF com
+	// imagens não seja considerado digitalizado.
+	minScannedText = 16
+
+	// maxFormDepth limita a recursão em XObjects de formulário.
+	maxFormDepth = 8
+)
+
+var objHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// pdfDoc é um PDF carregado em memória. Os objetos são localizados por busca
+// no arquivo (e não pela tabela xref), o que tolera arquivos com a tabela
+// corrompida e atualizações incrementais: a última definição prevalece.
+type pdfDoc struct {
+	data    []byte
+	objects map[int]any
+	order   []int
+	trailer pdfDict
+	fonts   map[int]*pdfFont
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// PDFText extrai o texto das primeiras maxPages páginas de um PDF (todas se
+// maxPages for menor ou igual a zero). Retorna ErrScanned se as páginas só
+// tiverem imagens e ErrEncrypted se o PDF for criptografado.
+func PDFText(data []byte, maxPages int) (string, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return "", errors.New("arquivo não é um PDF")
+	}
+
+	d := loadPDF(data)
+	if d.trailer["Encrypt"] != nil {
+		return "", ErrEncrypted
+	}
+
+	pages := d.pages(maxPages)
+	if len(pages) == 0 {
+		return "", errors.New("PDF sem páginas legíveis")
+	}
+
+	var w textWriter
+	images := 0
+	for _, pg := range pages {
+		d.runContent(d.pageContent(pg.dict), pg.resources, &w, &images, 0)
+		w.newline()
+		if w.sb.Len() >= maxTextBytes {
+			break
+		}
+	}
+
+	text := w.sb.String()
+	if images > 0 && countAlnum(text) < minScannedText {
+		return "", ErrScanned
+	}
+	return text, nil
+}
+
+func loadPDF(data []byte) *pdfDoc {
+	d := &pdfDoc{
+		data:    data,
+		objects: make(map[int]any),
+		trailer: pdfDict{},
+		fonts:   make(map[int]*pdfFont),
+	}
+
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		if obj, ok := d.parseIndirect(m[1]); ok {
+			d.objects[num] = obj
+			d.order = append(d.order, num)
+		}
+	}
+
+	// Trailers clássicos e streams de xref (PDF 1.5+) carregam Root e Encrypt
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		i += j + len("trailer")
+		if dict, ok := d.parseAt(i).(pdfDict); ok {
+			d.mergeTrailer(dict)
+		}
+	}
+	for _, num := range d.order {
+		s, ok := d.objects[num].(*pdfStream)
+		if !ok {
+			continue
+		}
+		switch d.name(s.dict["Type"]) {
+		case "XRef":
+			d.mergeTrailer(s.dict)
+		case "ObjStm":
+			d.loadObjStm(s)
+		}
+	}
+	return d
+}
+
+func (d *pdfDoc) mergeTrailer(dict pdfDict) {
+	for _, key := range []pdfName{"Root", "Encrypt", "Info"} {
+		if v, ok := dict[key]; ok {
+			d.trailer[key] = v
+		}
+	}
+}
+
+func (d *pdfDoc) parseAt(pos int) any {
+	v, err := newParser(d.data, pos).object()
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// parseIndirect lê o objeto que começa em pos (logo após "N G obj"),
+// incluindo os dados do stream, se houver.
+func (d *pdfDoc) parseIndirect(pos int) (any, bool) {
+	p := newParser(d.data, pos)
+	obj, err := p.object()
+	if err != nil {
+		return nil, false
+	}
+	dict, ok := obj.(pdfDict)
+	if !ok {
+		return obj, true
+	}
+
+	t := p.peek(0)
+	if t.kind != tokKeyword || t.str != "stream" || len(p.peeked) > 1 {
+		return dict, true
+	}
+	p.next()
+
+	start := p.lex.pos
+	if start < len(d.data) && d.data[start] == '\r' {
+		start++
+	}
+	if start < len(d.data) && d.data[start] == '\n' {
+		start++
+	}
+
+	// /Length direto é confiável se for seguido de endstream; se for
+	// referência ou estiver errado, procura o fim do stream
+	end := -1
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && start+int(n) <= len(d.data) {
+		e := start + int(n)
+		rest := bytes.TrimLeft(d.data[e:min(e+32, len(d.data))], "\x00\t\n\f\r ")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			end = e
+		}
+	}
+	if end < 0 {
+		k := bytes.Index(d.data[start:], []byte("endstream"))
+		if k < 0 {
+			return dict, true
+		}
+		end = start + k
+		if end > start && d.data[end-1] == '\n' {
+			end--
+		}
+		if end > start && d.data[end-1] == '\r' {
+			end--
+		}
+	}
+
+	return &pdfStream{dict: dict, data: d.data[start:end]}, true
+}
+
+// loadObjStm carrega os objetos de um stream de objetos (PDF 1.5+). Objetos
+// já definidos fora de streams têm precedência.
+func (d *pdfDoc) loadObjStm(s *pdfStream) {
+	raw, err := d.decode(s)
+	if err != nil {
+		return
+	}
+	n, _ := d.number(s.dict["N"])
+	first, _ := d.number(s.dict["First"])
+
+	lex := &lexer{data: raw}
+	for i := 0; i < int(n); i++ {
+		num, off := lex.next(), lex.next()
+		if num.kind != tokNumber || off.kind != tokNumber {
+			return
+		}
+		if _, exists := d.objects[int(num.num)]; exists {
+			continue
+		}
+		pos := int(first) + int(off.num)
+		if pos < 0 || pos >= len(raw) {
+			continue
+		}
+		if v, err := newParser(raw, pos).object(); err == nil {
+			d.objects[int(num.num)] = v
+		}
+	}
+}
+
+// resolve segue referências indiretas até um objeto direto.
+func (d *pdfDoc) resolve(v any) any {
+	for range 32 {
+		r, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[r.num]
+	}
+	return nil
+}
+
+func (d *pdfDoc) dict(v any) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.dict
+	}
+	return nil
+}
+
+func (d *pdfDoc) name(v any) pdfName {
+	n, _ := d.resolve(v).(pdfName)
+	return n
+}
+
+func (d *pdfDoc) number(v any) (float64, bool) {
+	n, ok := d.resolve(v).(float64)
+	return n, ok
+}
+
+// decode aplica os filtros do stream.
+func (d *pdfDoc) decode(s *pdfStream) ([]byte, error) {
+	var filters []pdfName
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []pdfName{f}
+	case pdfArray:
+		for _, v := range f {
+			filters = append(filters, d.name(v))
+		}
+	}
+
+	data := s.data
+	for _, f := range filters {
+		var err error
+		switch f {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data = []byte((&lexer{data: data}).readHex())
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("filtro PDF não suportado: %s", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		// Alguns geradores omitem o cabeçalho zlib
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, maxDecodedStream))
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("erro ao descompactar stream PDF: %w", err)
+	}
+	// Streams truncados ainda rendem o texto lido até o erro
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+
+	out := make([]byte, len(data)*4/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar ASCII85: %w", err)
+	}
+	return out[:n], nil
+}
+
+// pages retorna as primeiras limit páginas na ordem da árvore de páginas, com
+// os recursos herdados dos nós pais.
+func (d *pdfDoc) pages(limit int) []pdfPage {
+	var pages []pdfPage
+	full := func() bool { return limit > 0 && len(pages) >= limit }
+
+	visited := make(map[int]bool)
+	var walk func(v any, res pdfDict, depth int)
+	walk = func(v any, res pdfDict, depth int) {
+		if full() || depth > maxNesting {
+			return
+		}
+		if r, ok := v.(pdfRef); ok {
+			if visited[r.num] {
+				return
+			}
+			visited[r.num] = true
+		}
+		node := d.dict(v)
+		if node == nil {
+			return
+		}
+		if r := d.dict(node["Resources"]); r != nil {
+			res = r
+		}
+
+		kids, hasKids := d.resolve(node["Kids"]).(pdfArray)
+		if typ := d.name(node["Type"]); typ == "Pages" || (typ != "Page" && hasKids) {
+			for _, kid := range kids {
+				walk(kid, res, depth+1)
+			}
+			return
+		}
+		pages = append(pages, pdfPage{dict: node, resources: res})
+	}
+
+	if catalog := d.dict(d.trailer["Root"]); catalog != nil {
+		walk(catalog["Pages"], nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// Sem árvore de páginas válida, usa os objetos /Type /Page na ordem
+	// dos números de objeto
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if full() {
+			break
+		}
+		if node := d.dict(d.objects[num]); d.name(node["Type"]) == "Page" {
+			pages = append(pages, pdfPage{dict: node, resources: d.dict(node["Resources"])})
+		}
+	}
+	return pages
+}
+
+// pageContent concatena os streams de conteúdo da página.
+func (d *pdfDoc) pageContent(page pdfDict) []byte {
+	var streams []any
+	switch c := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = []any{c}
+	case pdfArray:
+		streams = c
+	}
+
+	var buf bytes.Buffer
+	for _, v := range streams {
+		s, ok := d.resolve(v).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decode(s)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// runContent interpreta os operadores de texto de um stream de conteúdo,
+// escrevendo o texto em w e contando as imagens desenhadas.
+func (d *pdfDoc) runContent(content []byte, res pdfDict, w *textWriter, images *int, depth int) {
+	p := newParser(content, 0)
+	var ops []any
+	var font *pdfFont
+	var lastY float64
+	haveY := false
+
+	show := func(v any) {
+		s, ok := v.(pdfString)
+		if !ok {
+			return
+		}
+		if font == nil {
+			w.write(decodeSimple(string(s), nil))
+			return
+		}
+		w.write(font.decode(string(s)))
+	}
+	last := func() any {
+		if len(ops) == 0 {
+			return nil
+		}
+		return ops[len(ops)-1]
+	}
+
+	for w.sb.Len() < maxTextBytes {
+		v, err := p.object()
+		if err != nil {
+			return
+		}
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			ops = append(ops, v)
+			continue
+		}
+
+		switch op {
+		case "BI":
+			p.peeked = nil
+			p.lex.skipInlineImage()
+			*images++
+
+		case "Tf":
+			if len(ops) >= 2 {
+				if name, ok := ops[len(ops)-2].(pdfName); ok {
+					font = d.font(res, name)
+				}
+			}
+
+		case "Tj":
+			show(last())
+
+		case "'", "\"":
+			w.newline()
+			show(last())
+
+		case "TJ":
+			arr, _ := last().(pdfArray)
+			for _, el := range arr {
+				if n, ok := el.(float64); ok {
+					// Deslocamentos grandes separam palavras
+					if n < -180 {
+						w.space()
+					}
+					continue
+				}
+				show(el)
+			}
+
+		case "Td", "TD":
+			if len(ops) >= 2 {
+				tx, _ := ops[len(ops)-2].(float64)
+				ty, _ := ops[len(ops)-1].(float64)
+				if math.Abs(ty) > 0.01 {
+					w.newline()
+				} else if tx > 0 {
+					w.space()
+				}
+			}
+
+		case "T*":
+			w.newline()
+
+		case "Tm":
+			if len(ops) >= 6 {
+				y, _ := ops[len(ops)-1].(float64)
+				if haveY && math.Abs(y-lastY) > 0.01 {
+					w.newline()
+				} else {
+					w.space()
+				}
+				lastY, haveY = y, true
+			}
+
+		case "ET":
+			w.space()
+
+		case "Do":
+			name, _ := last().(pdfName)
+			xobj, ok := d.resolve(d.dict(res["XObject"])[name]).(*pdfStream)
+			if !ok {
+				break
+			}
+			switch d.name(xobj.dict["Subtype"]) {
+			case "Image":
+				*images++
+			case "Form":
+				if depth >= maxFormDepth {
+					break
+				}
+				data, err := d.decode(xobj)
+				if err != nil {
+					break
+				}
+				formRes := d.dict(xobj.dict["Resources"])
+				if formRes == nil {
+					formRes = res
+				}
+				d.runContent(data, formRes, w, images, depth+1)
+			}
+		}
+		ops = ops[:0]
+	}
+}
+
+// textWriter junta o texto extraído sem repetir espaços e quebras de linha.
+type textWriter struct {
+	sb   strings.Builder
+	last rune
+}
+
+func (w *textWriter) write(s string) {
+	for _, r := range s {
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			if unicode.IsSpace(r) {
+				w.space()
+			}
+			continue
+		}
+		w.sb.WriteRune(r)
+		w.last = r
+	}
+}
+
+func (w *textWriter) space() {
+	if w.sb.Len() > 0 && w.last != ' ' && w.last != '\n' {
+		w.sb.WriteByte(' ')
+		w.last = ' '
+	}
+}
+
+func (w *textWriter) newline() {
+	if w.sb.Len() > 0 && w.last != '\n' {
+		w.sb.WriteByte('\n')
+		w.last = '\n'
+	}
+}
+
+func countAlnum(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
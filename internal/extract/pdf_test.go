@@ -0,0 +1,106 @@
+package extract
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// lines normaliza o texto extraído em linhas sem espaços nas pontas.
+func lines(s string) []string {
+	var out []string
+	for line := range strings.Lines(s) {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func TestPDFText(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		maxPages int
+		want     []string
+		err      error
+	}{
+		{"texto em duas páginas", "text.pdf", 0,
+			[]string{"Boleto de energia", "Vencimento 10/01/2024", "Cobrança", "Segunda página"}, nil},
+		{"só a primeira página", "text.pdf", 1,
+			[]string{"Boleto de energia", "Vencimento 10/01/2024", "Cobrança"}, nil},
+		{"stream de objetos, ToUnicode e Differences", "objstm.pdf", 0,
+			[]string{"Não pago", "Título"}, nil},
+		{"imagem com texto", "image_text.pdf", 0,
+			[]string{"Fatura do cartão de crédito"}, nil},
+		{"digitalizado", "scanned.pdf", 0, nil, ErrScanned},
+		{"criptografado", "encrypted.pdf", 0, nil, ErrEncrypted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := PDFText(readFixture(t, tt.file), tt.maxPages)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("PDFText = %q, %v; want erro %v", text, err, tt.err)
+			}
+			if got := lines(text); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("PDFText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFTextNotPDF(t *testing.T) {
+	if _, err := PDFText([]byte("texto qualquer"), 0); err == nil {
+		t.Error("PDFText de um arquivo que não é PDF deveria falhar")
+	}
+}
+
+func TestLexerStrings(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`(simples)`, "simples"},
+		{`(com (parênteses) aninhados)`, "com (parênteses) aninhados"},
+		{`(escapes \(\) \\ \n)`, "escapes () \\ \n"},
+		{`(octal \101\60\0610)`, "octal A0" + "10"},
+		{"(continua\\\nção)", "continuação"},
+		{`<48 65 6C6C6F>`, "Hello"},
+		{`<4F4>`, "O@"},
+	}
+	for _, tt := range tests {
+		tok := (&lexer{data: []byte(tt.in)}).next()
+		if tok.kind != tokString || tok.str != tt.want {
+			t.Errorf("%s = %q (tipo %d), want %q", tt.in, tok.str, tok.kind, tt.want)
+		}
+	}
+}
+
+func TestDecodeSimple(t *testing.T) {
+	tests := []struct {
+		in          string
+		differences map[byte]rune
+		want        string
+	}{
+		{"Cobran\xe7a", nil, "Cobrança"},
+		{"\x93aspas\x94", nil, "“aspas”"},
+		{"T\x01tulo", map[byte]rune{1: 'í'}, "Título"},
+	}
+	for _, tt := range tests {
+		if got := decodeSimple(tt.in, tt.differences); got != tt.want {
+			t.Errorf("decodeSimple(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxCMapEntries limita o tamanho de um CMap ToUnicode.
+const maxCMapEntries = 1 << 17
+
+// pdfFont converte os códigos de caractere de uma fonte em texto. Usa o CMap
+// ToUnicode quando existe; fontes simples sem ele caem na codificação
+// WinAnsi com as diferenças (/Differences) declaradas.
+type pdfFont struct {
+	toUnicode   map[string]string
+	codeLen     int
+	composite   bool
+	differences map[byte]rune
+}
+
+// font retorna a fonte name dos recursos res, ou nil se não existir.
+func (d *pdfDoc) font(res pdfDict, name pdfName) *pdfFont {
+	raw := d.dict(res["Font"])[name]
+	ref, isRef := raw.(pdfRef)
+	if isRef {
+		if f, ok := d.fonts[ref.num]; ok {
+			return f
+		}
+	}
+
+	fd := d.dict(raw)
+	if fd == nil {
+		return nil
+	}
+
+	f := &pdfFont{codeLen: 1}
+	if d.name(fd["Subtype"]) == "Type0" {
+		f.composite = true
+		f.codeLen = 2
+	}
+	if s, ok := d.resolve(fd["ToUnicode"]).(*pdfStream); ok {
+		if data, err := d.decode(s); err == nil {
+			f.parseCMap(data)
+		}
+	}
+	if enc := d.dict(fd["Encoding"]); enc != nil && !f.composite {
+		f.parseDifferences(d, d.resolve(enc["Differences"]))
+	}
+
+	if isRef {
+		d.fonts[ref.num] = f
+	}
+	return f
+}
+
+func (f *pdfFont) decode(s string) string {
+	if f.toUnicode == nil {
+		if f.composite {
+			// Sem ToUnicode, os códigos de uma fonte composta são IDs de
+			// glifos, que não dá para converter em texto
+			return ""
+		}
+		return decodeSimple(s, f.differences)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		n := min(f.codeLen, len(s)-i)
+		if u, ok := f.toUnicode[s[i:i+n]]; ok {
+			sb.WriteString(u)
+		} else if !f.composite {
+			sb.WriteString(decodeSimple(s[i:i+n], f.differences))
+		}
+		i += n
+	}
+	return sb.String()
+}
+
+func (f *pdfFont) parseCMap(data []byte) {
+	f.toUnicode = make(map[string]string)
+
+	p := newParser(data, 0)
+	var ops []any
+	for len(f.toUnicode) < maxCMapEntries {
+		v, err := p.object()
+		if err != nil {
+			return
+		}
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			ops = append(ops, v)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if lo, ok := firstOf(ops).(pdfString); ok && len(lo) > 0 && len(lo) <= 4 {
+				f.codeLen = len(lo)
+			}
+
+		case "endbfchar":
+			for i := 0; i+1 < len(ops); i += 2 {
+				src, ok1 := ops[i].(pdfString)
+				dst, ok2 := ops[i+1].(pdfString)
+				if ok1 && ok2 {
+					f.toUnicode[string(src)] = utf16Text(dst)
+				}
+			}
+
+		case "endbfrange":
+			for i := 0; i+2 < len(ops); i += 3 {
+				lo, ok1 := ops[i].(pdfString)
+				hi, ok2 := ops[i+1].(pdfString)
+				if ok1 && ok2 {
+					f.addRange(lo, hi, ops[i+2])
+				}
+			}
+		}
+		ops = ops[:0]
+	}
+}
+
+func (f *pdfFont) addRange(lo, hi pdfString, dst any) {
+	if len(lo) != len(hi) || len(lo) == 0 || len(lo) > 4 {
+		return
+	}
+	a, b := codeValue(lo), codeValue(hi)
+	if b < a || b-a > 0xFFFF {
+		return
+	}
+
+	for c := a; c <= b && len(f.toUnicode) < maxCMapEntries; c++ {
+		code := codeBytes(c, len(lo))
+		switch t := dst.(type) {
+		case pdfString:
+			// O destino incrementa a partir do último código UTF-16
+			units := utf16Units(t)
+			if len(units) == 0 {
+				return
+			}
+			units[len(units)-1] += uint16(c - a)
+			f.toUnicode[code] = string(utf16.Decode(units))
+		case pdfArray:
+			if i := int(c - a); i < len(t) {
+				if s, ok := t[i].(pdfString); ok {
+					f.toUnicode[code] = utf16Text(s)
+				}
+			}
+		}
+	}
+}
+
+func (f *pdfFont) parseDifferences(d *pdfDoc, v any) {
+	arr, ok := v.(pdfArray)
+	if !ok {
+		return
+	}
+
+	f.differences = make(map[byte]rune)
+	code := 0
+	for _, el := range arr {
+		switch t := d.resolve(el).(type) {
+		case float64:
+			code = int(t)
+		case pdfName:
+			if code >= 0 && code <= 0xFF {
+				if r, ok := glyphRune(string(t)); ok {
+					f.differences[byte(code)] = r
+				}
+			}
+			code++
+		}
+	}
+}
+
+func firstOf(ops []any) any {
+	if len(ops) == 0 {
+		return nil
+	}
+	return ops[0]
+}
+
+func codeValue(s pdfString) uint32 {
+	var v uint32
+	for i := 0; i < len(s); i++ {
+		v = v<<8 | uint32(s[i])
+	}
+	return v
+}
+
+func codeBytes(v uint32, n int) string {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return string(b)
+}
+
+func utf16Units(s pdfString) []uint16 {
+	units := make([]uint16, 0, (len(s)+1)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return units
+}
+
+func utf16Text(s pdfString) string {
+	return string(utf16.Decode(utf16Units(s)))
+}
+
+// winAnsiHigh são os caracteres de 0x80 a 0x9F da codificação WinAnsi
+// (Windows-1252); de 0xA0 em diante ela coincide com o Latin-1.
+var winAnsiHigh = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+// decodeSimple decodifica os bytes de uma fonte simples como WinAnsi,
+// aplicando as diferenças da fonte.
+func decodeSimple(s string, differences map[byte]rune) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if r, ok := differences[c]; ok {
+			sb.WriteRune(r)
+			continue
+		}
+		switch {
+		case c >= 0x80 && c < 0xA0:
+			if r := winAnsiHigh[c-0x80]; r != 0 {
+				sb.WriteRune(r)
+			}
+		default:
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
+
+// glyphNames cobre os nomes de glifos mais comuns em /Differences, incluindo
+// as letras acentuadas do português.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "quoteright": '’',
+	"quoteleft": '‘', "parenleft": '(', "parenright": ')', "asterisk": '*',
+	"plus": '+', "comma": ',', "hyphen": '-', "period": '.', "slash": '/',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>',
+	"question": '?', "at": '@', "underscore": '_', "endash": '–', "emdash": '—',
+	"bullet": '•', "quotedblleft": '“', "quotedblright": '”', "degree": '°',
+	"ordfeminine": 'ª', "ordmasculine": 'º', "section": '§', "Euro": '€',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"aacute": 'á', "agrave": 'à', "acircumflex": 'â', "atilde": 'ã', "adieresis": 'ä',
+	"eacute": 'é', "egrave": 'è', "ecircumflex": 'ê', "iacute": 'í', "icircumflex": 'î',
+	"oacute": 'ó', "ocircumflex": 'ô', "otilde": 'õ', "uacute": 'ú', "udieresis": 'ü',
+	"ccedilla": 'ç', "ntilde": 'ñ',
+	"Aacute": 'Á', "Agrave": 'À', "Acircumflex": 'Â', "Atilde": 'Ã', "Adieresis": 'Ä',
+	"Eacute": 'É', "Egrave": 'È', "Ecircumflex": 'Ê', "Iacute": 'Í', "Icircumflex": 'Î',
+	"Oacute": 'Ó', "Ocircumflex": 'Ô', "Otilde": 'Õ', "Uacute": 'Ú', "Udieresis": 'Ü',
+	"Ccedilla": 'Ç', "Ntilde": 'Ñ',
+}
+
+func glyphRune(name string) (rune, bool) {
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	if r, ok := glyphNames[name]; ok {
+		return r, true
+	}
+	// uniXXXX
+	if len(name) == 7 && strings.HasPrefix(name, "uni") {
+		if v, err := strconv.ParseUint(name[3:], 16, 16); err == nil {
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,396 @@
+package extract
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Tipos dos objetos PDF. Números são float64, booleanos bool e null nil.
+type (
+	pdfName    string
+	pdfString  string // bytes brutos da string literal ou hexadecimal
+	pdfKeyword string // operadores e palavras-chave (obj, stream, Tj...)
+	pdfArray   []any
+	pdfDict    map[pdfName]any
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+// maxNesting limita o aninhamento de arrays e dicionários, para que um
+// arquivo malformado não estoure a pilha.
+const maxNesting = 64
+
+var errSyntax = errors.New("sintaxe PDF inválida")
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokName
+	tokString
+	tokKeyword
+	tokDictStart
+	tokDictEnd
+	tokArrayStart
+	tokArrayEnd
+)
+
+type token struct {
+	kind tokenKind
+	num  float64
+	str  string
+}
+
+// lexer separa os tokens de um trecho PDF (corpo do arquivo, stream de
+// conteúdo ou CMap).
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) next() token {
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return token{kind: tokEOF}
+		}
+
+		c := l.data[l.pos]
+		switch c {
+		case '/':
+			l.pos++
+			return token{kind: tokName, str: l.readName()}
+		case '(':
+			l.pos++
+			return token{kind: tokString, str: l.readLiteral()}
+		case '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				l.pos += 2
+				return token{kind: tokDictStart}
+			}
+			l.pos++
+			return token{kind: tokString, str: l.readHex()}
+		case '>':
+			l.pos++
+			if l.pos < len(l.data) && l.data[l.pos] == '>' {
+				l.pos++
+				return token{kind: tokDictEnd}
+			}
+			continue
+		case '[':
+			l.pos++
+			return token{kind: tokArrayStart}
+		case ']':
+			l.pos++
+			return token{kind: tokArrayEnd}
+		case ')', '{', '}':
+			l.pos++
+			continue
+		}
+
+		word := l.readWord()
+		if c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9') {
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				return token{kind: tokNumber, num: n}
+			}
+		}
+		return token{kind: tokKeyword, str: word}
+	}
+}
+
+func (l *lexer) readWord() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *lexer) readName() string {
+	word := l.readWord()
+	if !strings.Contains(word, "#") {
+		return word
+	}
+
+	// Nomes podem ter bytes escapados como #xx
+	var b []byte
+	for i := 0; i < len(word); i++ {
+		if word[i] == '#' && i+2 < len(word) {
+			if v, err := strconv.ParseUint(word[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, word[i])
+	}
+	return string(b)
+}
+
+func (l *lexer) readLiteral() string {
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(b)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return string(b)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Continuação de linha
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+func (l *lexer) readHex() string {
+	var b []byte
+	var hi byte
+	odd := false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		v, ok := hexValue(c)
+		if !ok {
+			continue
+		}
+		if odd {
+			b = append(b, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	if odd {
+		b = append(b, hi<<4)
+	}
+	return string(b)
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// skipInlineImage pula os dados binários de uma imagem inline (BI ... ID
+// dados EI) a partir da posição logo após o operador BI.
+func (l *lexer) skipInlineImage() {
+	for {
+		t := l.next()
+		if t.kind == tokEOF {
+			return
+		}
+		if t.kind == tokKeyword && t.str == "ID" {
+			break
+		}
+	}
+
+	// Os dados terminam em "EI" cercado por espaços
+	for i := l.pos + 1; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// parser monta objetos PDF a partir dos tokens do lexer.
+type parser struct {
+	lex    *lexer
+	peeked []token
+}
+
+func newParser(data []byte, pos int) *parser {
+	return &parser{lex: &lexer{data: data, pos: pos}}
+}
+
+func (p *parser) next() token {
+	if len(p.peeked) > 0 {
+		t := p.peeked[0]
+		p.peeked = p.peeked[1:]
+		return t
+	}
+	return p.lex.next()
+}
+
+func (p *parser) peek(i int) token {
+	for len(p.peeked) <= i {
+		p.peeked = append(p.peeked, p.lex.next())
+	}
+	return p.peeked[i]
+}
+
+// object lê o próximo objeto. Palavras-chave que não são objetos (operadores)
+// retornam como pdfKeyword; o fim dos dados retorna errSyntax.
+func (p *parser) object() (any, error) {
+	return p.objectDepth(0)
+}
+
+func (p *parser) objectDepth(depth int) (any, error) {
+	if depth > maxNesting {
+		return nil, errSyntax
+	}
+
+	t := p.next()
+	for t.kind == tokDictEnd || t.kind == tokArrayEnd {
+		// Delimitador de fechamento solto
+		t = p.next()
+	}
+
+	switch t.kind {
+	case tokEOF:
+		return nil, errSyntax
+
+	case tokNumber:
+		// "num gen R" é uma referência indireta
+		if t.num == float64(int(t.num)) {
+			gen, r := p.peek(0), p.peek(1)
+			if gen.kind == tokNumber && r.kind == tokKeyword && r.str == "R" {
+				p.next()
+				p.next()
+				return pdfRef{num: int(t.num), gen: int(gen.num)}, nil
+			}
+		}
+		return t.num, nil
+
+	case tokName:
+		return pdfName(t.str), nil
+
+	case tokString:
+		return pdfString(t.str), nil
+
+	case tokArrayStart:
+		var arr pdfArray
+		for {
+			if p.peek(0).kind == tokArrayEnd {
+				p.next()
+				return arr, nil
+			}
+			v, err := p.objectDepth(depth + 1)
+			if err != nil {
+				return arr, err
+			}
+			arr = append(arr, v)
+		}
+
+	case tokDictStart:
+		dict := pdfDict{}
+		for {
+			k := p.next()
+			switch k.kind {
+			case tokDictEnd:
+				return dict, nil
+			case tokName:
+				v, err := p.objectDepth(depth + 1)
+				if err != nil {
+					return dict, err
+				}
+				dict[pdfName(k.str)] = v
+			case tokEOF:
+				return dict, errSyntax
+			}
+		}
+
+	case tokKeyword:
+		switch t.str {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfKeyword(t.str), nil
+	}
+	return nil, errSyntax
+}
@@ -0,0 +1,183 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Tipos de operação registrados no diário.
+const (
+	OpCreateFolder = "create_folder"
+	OpMove         = "move"
+	OpRename       = "rename"
+	OpMoveRename   = "move_rename"
+	// OpUndo marca que a sessão SessionID foi desfeita.
+	OpUndo = "undo"
+)
+
+// Entry é uma operação registrada no diário.
+type Entry struct {
+	SessionID  string    `json:"session_id"`
+	Time       time.Time `json:"time"`
+	Op         string    `json:"op"`
+	Backend    string    `json:"backend"`
+	FileID     string    `json:"file_id,omitempty"`
+	NewFileID  string    `json:"new_file_id,omitempty"`
+	OldParents []string  `json:"old_parents,omitempty"`
+	OldName    string    `json:"old_name,omitempty"`
+	NewParents []string  `json:"new_parents,omitempty"`
+	NewName    string    `json:"new_name,omitempty"`
+}
+
+// CurrentID retorna o ID do item depois da operação. Só difere de FileID em
+// backends cujo ID depende da localização, como o backend local.
+func (e Entry) CurrentID() string {
+	if e.NewFileID != "" {
+		return e.NewFileID
+	}
+	return e.FileID
+}
+
+// Journal é um diário de operações somente-anexo em disco (JSON Lines).
+// Cada entrada é gravada e sincronizada antes de Append retornar.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open abre (ou cria) o diário em path para anexar entradas.
+func Open(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do diário: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir diário: %w", err)
+	}
+
+	return &Journal{path: path, file: f}, nil
+}
+
+// Append grava uma entrada no diário. Time é preenchido se estiver vazio.
+func (j *Journal) Append(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar entrada do diário: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("erro ao gravar diário: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("erro ao sincronizar diário: %w", err)
+	}
+	return nil
+}
+
+// Close fecha o diário.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+// ReadAll lê todas as entradas do diário em path, na ordem em que foram gravadas.
+// Um diário inexistente resulta em uma lista vazia.
+func ReadAll(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir diário: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	line := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// Uma linha truncada (ex: queda de energia) não invalida o resto
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler diário (linha %d): %w", line, err)
+	}
+	return entries, nil
+}
+
+// Session resume as operações de uma sessão.
+type Session struct {
+	ID         string
+	Backend    string
+	Start      time.Time
+	End        time.Time
+	Operations int
+	Undone     bool
+}
+
+// Sessions agrupa as entradas por sessão, na ordem de início.
+func Sessions(entries []Entry) []Session {
+	var sessions []Session
+	index := make(map[string]int)
+	undone := make(map[string]bool)
+
+	for _, e := range entries {
+		if e.Op == OpUndo {
+			undone[e.SessionID] = true
+			continue
+		}
+
+		i, ok := index[e.SessionID]
+		if !ok {
+			i = len(sessions)
+			index[e.SessionID] = i
+			sessions = append(sessions, Session{ID: e.SessionID, Backend: e.Backend, Start: e.Time})
+		}
+		sessions[i].End = e.Time
+		sessions[i].Operations++
+	}
+
+	for i := range sessions {
+		sessions[i].Undone = undone[sessions[i].ID]
+	}
+	return sessions
+}
+
+// SessionEntries retorna as operações de uma sessão, na ordem original.
+func SessionEntries(entries []Entry, sessionID string) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if e.SessionID == sessionID && e.Op != OpUndo {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// NewSessionID gera um ID de sessão legível e ordenável. O sufixo aleatório
+// evita colisão entre execuções iniciadas no mesmo segundo.
+func NewSessionID() string {
+	return fmt.Sprintf("%s-%04x", time.Now().Format("20060102-150405"), rand.IntN(0x10000))
+}
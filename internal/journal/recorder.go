@@ -0,0 +1,123 @@
+package journal
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// Recorder envolve um drive.Backend e registra no diário cada operação que
+// altera o Drive, com o estado anterior necessário para desfazê-la.
+type Recorder struct {
+	drive.Backend
+	journal   *Journal
+	sessionID string
+	label     string
+}
+
+// NewRecorder cria um Recorder para a sessão informada. label identifica o
+// backend (ex: "drive" ou "local:/caminho") para que o undo use o mesmo.
+func NewRecorder(b drive.Backend, j *Journal, sessionID, label string) *Recorder {
+	return &Recorder{Backend: b, journal: j, sessionID: sessionID, label: label}
+}
+
+// SessionID retorna o ID da sessão registrada.
+func (r *Recorder) SessionID() string {
+	return r.sessionID
+}
+
+// CreateFolder cria a pasta e registra a criação.
+func (r *Recorder) CreateFolder(ctx context.Context, name string, parentID string) (*drive.FileInfo, error) {
+	f, err := r.Backend.CreateFolder(ctx, name, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.record(Entry{
+		Op:         OpCreateFolder,
+		FileID:     f.ID,
+		NewParents: f.Parents,
+		NewName:    f.Name,
+	})
+	return f, nil
+}
+
+// MoveFile move o arquivo e registra os pais anteriores.
+func (r *Recorder) MoveFile(ctx context.Context, fileID string, newParentID string, oldParentID string) (*drive.FileInfo, error) {
+	before := r.snapshot(ctx, fileID)
+
+	f, err := r.Backend.MoveFile(ctx, fileID, newParentID, oldParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordChange(OpMove, fileID, before, f)
+	return f, nil
+}
+
+// RenameFile renomeia o arquivo e registra o nome anterior.
+func (r *Recorder) RenameFile(ctx context.Context, fileID string, newName string) (*drive.FileInfo, error) {
+	before := r.snapshot(ctx, fileID)
+
+	f, err := r.Backend.RenameFile(ctx, fileID, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordChange(OpRename, fileID, before, f)
+	return f, nil
+}
+
+// MoveAndRenameFile move e renomeia o arquivo e registra o estado anterior.
+func (r *Recorder) MoveAndRenameFile(ctx context.Context, fileID string, newName string, newParentID string, oldParentID string) (*drive.FileInfo, error) {
+	before := r.snapshot(ctx, fileID)
+
+	f, err := r.Backend.MoveAndRenameFile(ctx, fileID, newName, newParentID, oldParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordChange(OpMoveRename, fileID, before, f)
+	return f, nil
+}
+
+// snapshot lê o estado atual do arquivo antes de alterá-lo. Sem ele a
+// operação ainda é executada, mas não poderá ser desfeita.
+func (r *Recorder) snapshot(ctx context.Context, fileID string) *drive.FileInfo {
+	f, err := r.Backend.GetFile(ctx, fileID)
+	if err != nil {
+		slog.Warn("não foi possível ler o estado do arquivo para o diário", "fileID", fileID, "error", err)
+		return nil
+	}
+	return f
+}
+
+func (r *Recorder) recordChange(op, fileID string, before, after *drive.FileInfo) {
+	if before == nil {
+		return
+	}
+
+	e := Entry{
+		Op:         op,
+		FileID:     fileID,
+		OldParents: before.Parents,
+		OldName:    before.Name,
+		NewParents: after.Parents,
+		NewName:    after.Name,
+	}
+	if after.ID != fileID {
+		e.NewFileID = after.ID
+	}
+	r.record(e)
+}
+
+// record grava a entrada. Uma falha no diário não desfaz a operação já
+// executada, então apenas é registrada no log.
+func (r *Recorder) record(e Entry) {
+	e.SessionID = r.sessionID
+	e.Backend = r.label
+	if err := r.journal.Append(e); err != nil {
+		slog.Error("erro ao registrar operação no diário", "op", e.Op, "fileID", e.FileID, "error", err)
+	}
+}
@@ -0,0 +1,154 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// UndoResult resume a execução de um undo.
+type UndoResult struct {
+	Reverted       int
+	FoldersTrashed int
+	Skipped        int
+	Errors         []error
+}
+
+// Undo reverte as operações de uma sessão em ordem inversa: arquivos voltam
+// ao nome e à pasta anteriores e as pastas criadas na sessão vão para a
+// lixeira se estiverem vazias. Itens alterados depois da sessão são pulados.
+// Com dryRun, apenas registra no log o que seria feito.
+func Undo(ctx context.Context, b drive.Backend, entries []Entry, dryRun bool) UndoResult {
+	var result UndoResult
+
+	// Em backends onde o ID muda ao mover, o undo de uma operação pode deixar
+	// o item em um ID diferente do registrado na operação anterior
+	remap := make(map[string]string)
+	resolve := func(id string) string {
+		if mapped, ok := remap[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result
+		}
+
+		e := entries[i]
+		switch e.Op {
+		case OpMove, OpRename, OpMoveRename:
+			id, err := revert(ctx, b, e, resolve(e.CurrentID()), dryRun)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("'%s': %w", e.NewName, err))
+				slog.Error("falha ao desfazer operação", "op", e.Op, "name", e.NewName, "error", err)
+				continue
+			}
+			if id == "" {
+				result.Skipped++
+				continue
+			}
+			remap[e.FileID] = id
+			result.Reverted++
+
+		case OpCreateFolder:
+			trashed, err := trashIfEmpty(ctx, b, resolve(e.FileID), e.NewName, dryRun)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("pasta '%s': %w", e.NewName, err))
+				slog.Error("falha ao remover pasta criada", "name", e.NewName, "error", err)
+				continue
+			}
+			if trashed {
+				result.FoldersTrashed++
+			} else {
+				result.Skipped++
+			}
+		}
+	}
+
+	return result
+}
+
+// revert devolve um item ao estado anterior à operação. Retorna o ID do item
+// depois do undo, ou "" se ele foi pulado.
+func revert(ctx context.Context, b drive.Backend, e Entry, currentID string, dryRun bool) (string, error) {
+	current, err := b.GetFile(ctx, currentID)
+	if err != nil {
+		slog.Warn("item não encontrado, pulando", "name", e.NewName, "fileID", currentID, "error", err)
+		return "", nil
+	}
+	if current.Trashed || current.Name != e.NewName || !sameParents(current.Parents, e.NewParents) {
+		slog.Warn("item foi alterado depois da sessão, pulando", "name", e.NewName, "current_name", current.Name, "current_parents", current.Parents)
+		return "", nil
+	}
+
+	oldParent := first(e.OldParents)
+	newParent := first(e.NewParents)
+
+	if dryRun {
+		slog.Info("[dry-run] desfaria operação", "op", e.Op, "name", e.NewName, "old_name", e.OldName, "old_parent", oldParent)
+		return currentID, nil
+	}
+
+	var restored *drive.FileInfo
+	switch {
+	case oldParent == newParent:
+		restored, err = b.RenameFile(ctx, currentID, e.OldName)
+	case current.Name == e.OldName:
+		restored, err = b.MoveFile(ctx, currentID, oldParent, newParent)
+	default:
+		restored, err = b.MoveAndRenameFile(ctx, currentID, e.OldName, oldParent, newParent)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	slog.Debug("operação desfeita", "op", e.Op, "name", e.OldName, "fileID", restored.ID)
+	return restored.ID, nil
+}
+
+// trashIfEmpty move a pasta para a lixeira se ela não tiver mais itens.
+func trashIfEmpty(ctx context.Context, b drive.Backend, folderID, name string, dryRun bool) (bool, error) {
+	folder, err := b.GetFile(ctx, folderID)
+	if err != nil || folder.Trashed {
+		slog.Warn("pasta criada não encontrada, pulando", "name", name, "fileID", folderID)
+		return false, nil
+	}
+
+	items, _, err := b.ListFilesPage(ctx, folderID, "")
+	if err != nil {
+		return false, err
+	}
+	if len(items) > 0 {
+		slog.Warn("pasta criada na sessão não está vazia, mantendo", "name", name, "items", len(items))
+		return false, nil
+	}
+
+	if dryRun {
+		slog.Info("[dry-run] moveria pasta vazia para a lixeira", "name", name)
+		return true, nil
+	}
+	if err := b.TrashFile(ctx, folderID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func sameParents(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+func first(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
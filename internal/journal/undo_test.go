@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+)
+
+// record aplica as entradas do plano através de um Recorder e retorna as
+// operações gravadas no diário.
+func record(t *testing.T, m *drive.MemoryBackend, entries ...plan.Entry) []Entry {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	j, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	sessionID := NewSessionID()
+	r := NewRecorder(m, j, sessionID, "drive")
+	for _, e := range entries {
+		if err := e.Apply(ctx, r, false); err != nil {
+			t.Fatalf("Apply(%s): %v", e.CurrentPath, err)
+		}
+	}
+	j.Close()
+
+	all, err := ReadAll(path)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return SessionEntries(all, sessionID)
+}
+
+func TestUndoRestoresFilesAndTrashesCreatedFolders(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	backup := m.AddFolder("backup")
+	boleto := m.AddFile("boleto.pdf", "application/pdf", 10, backup.ID)
+	foto := m.AddFile("IMG_0001.jpg", "image/jpeg", 10, backup.ID)
+
+	e1 := plan.NewEntry(boleto, "backup/boleto.pdf")
+	e1.TargetFolder = "Financeiro/Boletos"
+	e1.TargetName = "Boleto Luz.pdf"
+	e2 := plan.NewEntry(foto, "backup/IMG_0001.jpg")
+	e2.TargetFolder = "Fotos"
+
+	entries := record(t, m, e1, e2)
+	// Três pastas criadas, uma movimentação com renomeação e uma simples
+	if len(entries) != 5 {
+		t.Fatalf("diário com %d operações, want 5: %+v", len(entries), entries)
+	}
+
+	res := Undo(ctx, m, entries, false)
+	if len(res.Errors) > 0 {
+		t.Fatalf("Undo: %v", res.Errors)
+	}
+	if res.Reverted != 2 || res.FoldersTrashed != 3 {
+		t.Errorf("Undo = %+v, want 2 revertidos e 3 pastas removidas", res)
+	}
+
+	for id, name := range map[string]string{boleto.ID: "boleto.pdf", foto.ID: "IMG_0001.jpg"} {
+		f, _ := m.GetFile(ctx, id)
+		if f.Name != name || len(f.Parents) != 1 || f.Parents[0] != backup.ID {
+			t.Errorf("%s: nome %q em %v, want %q em [%s]", id, f.Name, f.Parents, name, backup.ID)
+		}
+	}
+	if f, _ := drive.FindNestedFolder(ctx, m, "Financeiro", m.RootID()); f != nil {
+		t.Error("pasta Financeiro criada na sessão continua fora da lixeira")
+	}
+}
+
+func TestUndoSkipsItemsChangedAfterSession(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	f := m.AddFile("extrato.pdf", "application/pdf", 10)
+
+	e := plan.NewEntry(f, "extrato.pdf")
+	e.TargetFolder = "Financeiro"
+	entries := record(t, m, e)
+
+	// O usuário renomeou o arquivo depois da sessão: o undo não deve mexer nele
+	if _, err := m.RenameFile(ctx, f.ID, "extrato-janeiro.pdf"); err != nil {
+		t.Fatalf("RenameFile: %v", err)
+	}
+
+	res := Undo(ctx, m, entries, false)
+	if res.Reverted != 0 || res.Skipped != 2 {
+		t.Errorf("Undo = %+v, want 0 revertidos e 2 pulados", res)
+	}
+	got, _ := m.GetFile(ctx, f.ID)
+	if got.Name != "extrato-janeiro.pdf" || got.Parents[0] == m.RootID() {
+		t.Errorf("arquivo alterado pelo undo: %q em %v", got.Name, got.Parents)
+	}
+}
@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// lockWait é quanto Lock espera outro processo liberar o plano.
+	lockWait = 30 * time.Second
+
+	// lockStale é a idade a partir da qual uma trava é considerada de um
+	// processo que morreu sem liberá-la. Quem segura a trava renova a data
+	// dela a cada lockRefresh, então operações longas (ex: uma chamada ao
+	// Drive em backoff) não a perdem.
+	lockStale   = 2 * time.Minute
+	lockRefresh = 15 * time.Second
+)
+
+// ErrLocked indica que outro processo segurou o plano além do tempo de espera.
+var ErrLocked = errors.New("plano em uso por outro processo")
+
+// Lock trava o plano em filePath contra alterações de outros processos (ex:
+// "watch" e "serve" sobre a mesma fila) até que unlock seja chamada. Quem lê,
+// altera e grava o plano deve segurar a trava durante as três etapas, relendo
+// o plano depois de travá-lo. A trava é um arquivo ao lado do plano, criado de
+// forma exclusiva, com o PID e um token do dono; unlock só o remove se ele
+// ainda for do mesmo dono.
+func Lock(filePath string) (unlock func(), err error) {
+	lockPath := filePath + ".lock"
+
+	token := make([]byte, 8)
+	rand.Read(token)
+	owner := fmt.Appendf(nil, "%d %s\n", os.Getpid(), hex.EncodeToString(token))
+
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.Write(owner)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("erro ao travar plano: %w", err)
+			}
+			return holdLock(lockPath, owner), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("erro ao travar plano: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			if stale, err := os.ReadFile(lockPath); err == nil {
+				removeLock(lockPath, stale)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w (%s)", ErrLocked, lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// holdLock renova a trava em lockPath enquanto ela for de owner e retorna a
+// função que a libera.
+func holdLock(lockPath string, owner []byte) (unlock func()) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(lockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if data, err := os.ReadFile(lockPath); err != nil || !bytes.Equal(data, owner) {
+					return
+				}
+				now := time.Now()
+				os.Chtimes(lockPath, now, now)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			removeLock(lockPath, owner)
+		})
+	}
+}
+
+// removeLock remove a trava em lockPath se ela ainda tiver o conteúdo owner.
+func removeLock(lockPath string, owner []byte) {
+	if data, err := os.ReadFile(lockPath); err == nil && bytes.Equal(data, owner) {
+		os.Remove(lockPath)
+	}
+}
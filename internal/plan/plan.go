@@ -0,0 +1,216 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// Version é a versão do formato do arquivo de plano.
+const Version = 1
+
+// ErrStale indica que o arquivo mudou no Drive desde que o plano foi gerado.
+var ErrStale = errors.New("arquivo mudou desde que o plano foi gerado")
+
+// Plan é o conjunto de sugestões gerado por "organize plan", para ser revisado
+// (e editado) antes de "organize apply".
+type Plan struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Backend   string    `json:"backend"`
+	Model     string    `json:"model"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Entry é a sugestão para um arquivo. Só as entradas com Approved são
+// aplicadas; TargetFolder e TargetName podem ser editados à mão. CurrentName
+// fica à parte de CurrentPath porque nomes no Drive podem conter "/".
+type Entry struct {
+	Approved        bool    `json:"approved"`
+	FileID          string  `json:"file_id"`
+	CurrentPath     string  `json:"current_path"`
+	CurrentName     string  `json:"current_name"`
+	CurrentParentID string  `json:"current_parent_id"`
+	Md5Checksum     string  `json:"md5_checksum,omitempty"`
+	ModifiedTime    string  `json:"modified_time,omitempty"`
+	TargetFolder    string  `json:"target_folder"`
+	TargetName      string  `json:"target_name"`
+	Reason          string  `json:"reason,omitempty"`
+	Confidence      float64 `json:"confidence"`
+}
+
+// NewEntry cria uma entrada para o arquivo f, localizado em filePath.
+func NewEntry(f *drive.FileInfo, filePath string) Entry {
+	e := Entry{
+		FileID:       f.ID,
+		CurrentPath:  filePath,
+		CurrentName:  f.Name,
+		Md5Checksum:  f.Md5Checksum,
+		ModifiedTime: f.ModifiedTime,
+	}
+	if len(f.Parents) > 0 {
+		e.CurrentParentID = f.Parents[0]
+	}
+	return e
+}
+
+// TargetPath retorna o caminho final do arquivo após aplicar a entrada.
+func (e Entry) TargetPath() string {
+	name := e.TargetName
+	if name == "" {
+		name = e.CurrentName
+	}
+	return path.Join(e.TargetFolder, name)
+}
+
+// Upsert adiciona a entrada ao plano, substituindo a do mesmo arquivo se já
+// houver uma.
+func (p *Plan) Upsert(e Entry) {
+	for i := range p.Entries {
+		if p.Entries[i].FileID == e.FileID {
+			p.Entries[i] = e
+			return
+		}
+	}
+	p.Entries = append(p.Entries, e)
+}
+
+// Remove tira do plano a entrada do arquivo fileID. Retorna false se não
+// houver entrada para ele.
+func (p *Plan) Remove(fileID string) bool {
+	for i := range p.Entries {
+		if p.Entries[i].FileID == fileID {
+			p.Entries = slices.Delete(p.Entries, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// Save grava o plano em filePath como JSON indentado.
+func (p *Plan) Save(filePath string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar plano: %w", err)
+	}
+
+	tmp := filePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("erro ao salvar plano: %w", err)
+	}
+	if err := os.Rename(tmp, filePath); err != nil {
+		return fmt.Errorf("erro ao salvar plano: %w", err)
+	}
+	return nil
+}
+
+// Load lê e valida um plano salvo com Save (possivelmente editado à mão).
+func Load(filePath string) (*Plan, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler plano: %w", err)
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar plano '%s': %w", filePath, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("versão de plano não suportada: %d (esperada %d)", p.Version, Version)
+	}
+
+	for i := range p.Entries {
+		e := &p.Entries[i]
+		e.TargetFolder = strings.Trim(strings.TrimSpace(e.TargetFolder), "/")
+		e.TargetName = strings.TrimSpace(e.TargetName)
+		// Planos antigos não têm current_name; o nome sai do caminho, o que
+		// só erra para nomes com "/"
+		if e.CurrentName == "" {
+			e.CurrentName = path.Base(e.CurrentPath)
+		}
+
+		if !e.Approved {
+			continue
+		}
+		if e.FileID == "" {
+			return nil, fmt.Errorf("entrada %d do plano sem file_id", i+1)
+		}
+		if e.TargetFolder == "" {
+			return nil, fmt.Errorf("entrada %d do plano (%s) sem target_folder", i+1, e.CurrentPath)
+		}
+		if strings.Contains(e.TargetName, "/") {
+			return nil, fmt.Errorf("entrada %d do plano (%s): target_name não pode conter '/'", i+1, e.CurrentPath)
+		}
+	}
+
+	return &p, nil
+}
+
+// Verify confere se o arquivo ainda está como quando o plano foi gerado:
+// mesmo nome, mesma pasta e mesmo conteúdo. Divergências retornam ErrStale.
+func (e Entry) Verify(ctx context.Context, b drive.Backend) (*drive.FileInfo, error) {
+	f, err := b.GetFile(ctx, e.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStale, err)
+	}
+
+	switch {
+	case f.Trashed:
+		return nil, fmt.Errorf("%w: está na lixeira", ErrStale)
+	case f.Name != e.CurrentName:
+		return nil, fmt.Errorf("%w: foi renomeado para '%s'", ErrStale, f.Name)
+	case e.CurrentParentID != "" && !slices.Contains(f.Parents, e.CurrentParentID):
+		return nil, fmt.Errorf("%w: foi movido para outra pasta", ErrStale)
+	case e.Md5Checksum != "" && f.Md5Checksum != e.Md5Checksum:
+		return nil, fmt.Errorf("%w: conteúdo alterado", ErrStale)
+	case e.Md5Checksum == "" && e.ModifiedTime != "" && f.ModifiedTime != e.ModifiedTime:
+		return nil, fmt.Errorf("%w: modificado em %s", ErrStale, f.ModifiedTime)
+	}
+
+	return f, nil
+}
+
+// Apply verifica a entrada e move (e renomeia, se for o caso) o arquivo para
+// a pasta de destino, criando as pastas necessárias.
+func (e Entry) Apply(ctx context.Context, b drive.Backend, dryRun bool) error {
+	f, err := e.Verify(ctx, b)
+	if err != nil {
+		return err
+	}
+	if !f.CanMove() {
+		return drive.ErrCannotMove
+	}
+	if dryRun {
+		return nil
+	}
+
+	dest, err := drive.FindOrCreateNestedFolder(ctx, b, e.TargetFolder, b.RootID())
+	if err != nil {
+		return fmt.Errorf("erro ao criar pasta destino: %w", err)
+	}
+
+	name := e.TargetName
+	if name == "" {
+		name = f.Name
+	}
+
+	switch {
+	case dest.ID == e.CurrentParentID && name == f.Name:
+		return nil
+	case dest.ID == e.CurrentParentID:
+		_, err = b.RenameFile(ctx, f.ID, name)
+	case name == f.Name:
+		_, err = b.MoveFile(ctx, f.ID, dest.ID, e.CurrentParentID)
+	default:
+		_, err = b.MoveAndRenameFile(ctx, f.ID, name, dest.ID, e.CurrentParentID)
+	}
+	return err
+}
@@ -0,0 +1,247 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func TestApplyMovesAndRenames(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	backup := m.AddFolder("backup")
+	f := m.AddFile("boleto.pdf", "application/pdf", 100, backup.ID)
+
+	e := NewEntry(f, "backup/boleto.pdf")
+	e.Approved = true
+	e.TargetFolder = "Financeiro/Boletos"
+	e.TargetName = "Boleto Luz 2024-01.pdf"
+
+	if err := e.Apply(ctx, m, false); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	dest, err := drive.FindNestedFolder(ctx, m, "Financeiro/Boletos", m.RootID())
+	if err != nil || dest == nil {
+		t.Fatalf("pasta de destino não criada: %v", err)
+	}
+	got, _ := m.GetFile(ctx, f.ID)
+	if got.Name != e.TargetName {
+		t.Errorf("nome = %q, want %q", got.Name, e.TargetName)
+	}
+	if len(got.Parents) != 1 || got.Parents[0] != dest.ID {
+		t.Errorf("parents = %v, want [%s]", got.Parents, dest.ID)
+	}
+
+	// A entrada já aplicada não confere mais com o arquivo
+	if err := e.Apply(ctx, m, false); !errors.Is(err, ErrStale) {
+		t.Errorf("segundo Apply = %v, want ErrStale", err)
+	}
+}
+
+func TestApplyDryRunChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	f := m.AddFile("extrato.pdf", "application/pdf", 100)
+
+	e := NewEntry(f, "extrato.pdf")
+	e.TargetFolder = "Financeiro/Extratos"
+	if err := e.Apply(ctx, m, true); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if dest, _ := drive.FindNestedFolder(ctx, m, "Financeiro", m.RootID()); dest != nil {
+		t.Error("dry-run criou a pasta de destino")
+	}
+	if got, _ := m.GetFile(ctx, f.ID); got.Parents[0] != m.RootID() {
+		t.Errorf("dry-run moveu o arquivo para %v", got.Parents)
+	}
+}
+
+func TestApplyWithoutMovePermission(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	f := m.AddFile("ata.pdf", "application/pdf", 10)
+	// Leitor de um drive compartilhado: pode renomear, mas não mover
+	m.SetCapabilities(f.ID, drive.Capabilities{CanRename: true})
+
+	e := NewEntry(f, "ata.pdf")
+	e.TargetFolder = "Atas"
+	if err := e.Apply(ctx, m, false); !errors.Is(err, drive.ErrCannotMove) {
+		t.Fatalf("Apply = %v, want ErrCannotMove", err)
+	}
+	if dest, _ := drive.FindNestedFolder(ctx, m, "Atas", m.RootID()); dest != nil {
+		t.Error("pasta de destino criada sem permissão para mover")
+	}
+}
+
+func TestVerifyDetectsChanges(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		change func(m *drive.MemoryBackend, f *drive.FileInfo)
+	}{
+		{"renomeado", func(m *drive.MemoryBackend, f *drive.FileInfo) {
+			m.RenameFile(ctx, f.ID, "outro.pdf")
+		}},
+		{"movido", func(m *drive.MemoryBackend, f *drive.FileInfo) {
+			m.MoveFile(ctx, f.ID, m.AddFolder("Outra").ID, m.RootID())
+		}},
+		{"conteúdo alterado", func(m *drive.MemoryBackend, f *drive.FileInfo) {
+			m.SetContent(f.ID, []byte("novo"))
+		}},
+		{"na lixeira", func(m *drive.MemoryBackend, f *drive.FileInfo) {
+			m.TrashFile(ctx, f.ID)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := drive.NewMemoryBackend()
+			f := m.AddFile("fatura.pdf", "application/pdf", 100)
+			m.SetContent(f.ID, []byte("original"))
+			f, _ = m.GetFile(ctx, f.ID)
+
+			e := NewEntry(f, "fatura.pdf")
+			e.TargetFolder = "Financeiro/Faturas"
+			tt.change(m, f)
+
+			if _, err := e.Verify(ctx, m); !errors.Is(err, ErrStale) {
+				t.Errorf("Verify = %v, want ErrStale", err)
+			}
+		})
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	m := drive.NewMemoryBackend()
+
+	p := &Plan{Version: Version, Backend: "drive"}
+	e := NewEntry(m.AddFile("a.pdf", "application/pdf", 1), "a.pdf")
+	e.Approved = true
+	e.TargetFolder = "Documentos"
+	p.Upsert(e)
+	e.TargetName = "b.pdf"
+	p.Upsert(e)
+
+	if err := p.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].TargetPath() != "Documentos/b.pdf" {
+		t.Errorf("entradas = %+v, want uma entrada para Documentos/b.pdf", loaded.Entries)
+	}
+}
+
+func TestApplyNameWithSlash(t *testing.T) {
+	ctx := context.Background()
+	m := drive.NewMemoryBackend()
+	// No Drive, "/" é um caractere válido em nomes
+	f := m.AddFile("Recibo 10/01.pdf", "application/pdf", 100)
+
+	e := NewEntry(f, "Recibo 10/01.pdf")
+	e.TargetFolder = "Financeiro/Comprovantes"
+	if got := e.TargetPath(); got != "Financeiro/Comprovantes/Recibo 10/01.pdf" {
+		t.Errorf("TargetPath = %q", got)
+	}
+	if err := e.Apply(ctx, m, false); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got, _ := m.GetFile(ctx, f.ID); got.Name != "Recibo 10/01.pdf" {
+		t.Errorf("nome = %q, want o original", got.Name)
+	}
+}
+
+func TestLoadFillsCurrentNameOfOlderPlans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	data := `{"version": 1, "backend": "drive", "entries": [
+		{"approved": true, "file_id": "1", "current_path": "backup/nota.pdf", "target_folder": "Financeiro"}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := p.Entries[0].CurrentName; got != "nota.pdf" {
+		t.Errorf("CurrentName = %q, want nota.pdf", got)
+	}
+}
+
+func TestLockExcludesAndExpires(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := Lock(path)
+		if err != nil {
+			t.Errorf("segundo Lock: %v", err)
+		} else {
+			unlock()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("segundo Lock não esperou a trava ser liberada")
+	case <-time.After(200 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+
+	// Uma trava esquecida por um processo que morreu é descartada
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStale)
+	os.Chtimes(lockPath, old, old)
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("Lock com trava antiga: %v", err)
+	}
+	unlock()
+}
+
+func TestUnlockKeepsLockTakenOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	lockPath := path + ".lock"
+
+	unlockOld, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	// Uma operação longa demais perde a trava para outro processo
+	old := time.Now().Add(-2 * lockStale)
+	os.Chtimes(lockPath, old, old)
+	unlockNew, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock com trava antiga: %v", err)
+	}
+
+	unlockOld()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("unlock do dono anterior removeu a trava atual: %v", err)
+	}
+	unlockNew()
+	if _, err := os.Stat(lockPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("trava continua no disco depois do unlock: %v", err)
+	}
+}
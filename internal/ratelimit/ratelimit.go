@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// logInterval é o intervalo entre os logs de vazão (nível debug).
+const logInterval = 10 * time.Second
+
+// Limiter é um limitador de taxa do tipo token bucket, usado para respeitar as
+// cotas da API do Drive e do provedor de IA. Acumula até burst permissões,
+// repostas à taxa de rate por segundo. Um *Limiter nil não limita nada.
+type Limiter struct {
+	name  string
+	rate  float64
+	burst float64
+
+	// now é o relógio do limitador; trocado nos testes
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	// Vazão desde o último log
+	windowStart time.Time
+	windowCount int
+	windowWait  time.Duration
+}
+
+// New cria um limitador com rate permissões por segundo e rajada de burst.
+// rate menor ou igual a zero desativa o limite (retorna nil).
+func New(name string, rate float64, burst int) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := time.Now()
+	return &Limiter{
+		name:        name,
+		rate:        rate,
+		burst:       float64(burst),
+		now:         time.Now,
+		tokens:      float64(burst),
+		last:        now,
+		windowStart: now,
+	}
+}
+
+// PerMinute cria um limitador com n permissões por minuto.
+func PerMinute(name string, n float64, burst int) *Limiter {
+	return New(name, n/60, burst)
+}
+
+// Wait bloqueia até haver uma permissão disponível ou ctx ser cancelado.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// A permissão reservada é devolvida para não penalizar as próximas
+		l.mu.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// reserve consome uma permissão e retorna quanto tempo esperar por ela.
+// O saldo pode ficar negativo: cada chamada reserva a próxima vaga na fila.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+	l.tokens--
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	l.windowCount++
+	l.windowWait += delay
+	if elapsed := now.Sub(l.windowStart); elapsed >= logInterval {
+		slog.Debug("vazão do limitador",
+			"limiter", l.name,
+			"req_per_s", float64(l.windowCount)/elapsed.Seconds(),
+			"limit_per_s", l.rate,
+			"waited", l.windowWait.Round(time.Millisecond))
+		l.windowStart = now
+		l.windowCount = 0
+		l.windowWait = 0
+	}
+
+	return delay
+}
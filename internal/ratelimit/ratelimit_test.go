@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock é um relógio que só avança quando o teste manda.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newFake(rate float64, burst int) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := New("teste", rate, burst)
+	l.now = clock.now
+	l.last = clock.t
+	l.windowStart = clock.t
+	return l, clock
+}
+
+func TestLimiterBurstAndRefill(t *testing.T) {
+	l, clock := newFake(2, 3) // 2 por segundo, rajada de 3
+
+	// A rajada sai sem espera
+	for i := range 3 {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("permissão %d da rajada esperou %v", i+1, d)
+		}
+	}
+	// As seguintes entram na fila, uma a cada 500ms
+	for i, want := range []time.Duration{500 * time.Millisecond, time.Second} {
+		if d := l.reserve(); d != want {
+			t.Errorf("permissão %d além da rajada esperou %v, want %v", i+1, d, want)
+		}
+	}
+
+	// Depois de 1s as duas reservadas foram repostas; mais 1s repõe duas
+	clock.advance(2 * time.Second)
+	for i := range 2 {
+		if d := l.reserve(); d != 0 {
+			t.Errorf("permissão %d reposta esperou %v", i+1, d)
+		}
+	}
+	if d := l.reserve(); d != 500*time.Millisecond {
+		t.Errorf("permissão sem saldo esperou %v, want 500ms", d)
+	}
+
+	// O saldo nunca passa da rajada, por mais tempo que passe
+	clock.advance(time.Hour)
+	for range 3 {
+		l.reserve()
+	}
+	if d := l.reserve(); d != 500*time.Millisecond {
+		t.Errorf("depois de ocioso, a 4ª permissão esperou %v, want 500ms", d)
+	}
+}
+
+func TestPerMinute(t *testing.T) {
+	if New("teste", 0, 1) != nil || PerMinute("teste", 0, 1) != nil {
+		t.Fatal("taxa 0 deveria desativar o limite")
+	}
+
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := PerMinute("teste", 30, 1)
+	l.now = clock.now
+	l.last = clock.t
+	l.reserve()
+	if d := l.reserve(); d != 2*time.Second {
+		t.Errorf("30 por minuto: espera de %v, want 2s", d)
+	}
+}
+
+func TestNilLimiterAndCancel(t *testing.T) {
+	var l *Limiter
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Wait de limitador nil = %v", err)
+	}
+
+	l, _ = newFake(1, 1)
+	l.reserve()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("Wait com contexto cancelado deveria falhar")
+	}
+	// A permissão reservada pelo Wait cancelado é devolvida
+	if l.tokens != 0 {
+		t.Errorf("saldo = %v depois do cancelamento, want 0", l.tokens)
+	}
+}
@@ -0,0 +1,253 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// maxDepth é a profundidade máxima percorrida, a mesma das listagens
+// recursivas do pacote drive.
+const maxDepth = 20
+
+// Node é uma pasta com os totais dela e de todas as subpastas.
+type Node struct {
+	Name     string  `json:"name"`
+	Path     string  `json:"path"`
+	Files    int     `json:"files"`
+	Size     int64   `json:"size"`
+	Children []*Node `json:"children,omitempty"`
+
+	// files são os arquivos diretamente na pasta
+	files []*drive.FileInfo
+}
+
+// Scan percorre recursivamente a pasta folderID, de caminho prefix ("" na
+// raiz), e soma arquivos e bytes de cada subpasta. Pastas que não puderem ser
+// listadas são registradas no log e ignoradas.
+func Scan(ctx context.Context, b drive.Backend, folderID, prefix string) (*Node, error) {
+	root := &Node{Name: path.Base(prefix), Path: prefix}
+	if prefix == "" {
+		root.Name = "/"
+	}
+	if err := scan(ctx, b, root, folderID, 0); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func scan(ctx context.Context, b drive.Backend, n *Node, folderID string, depth int) error {
+	if depth > maxDepth {
+		slog.Warn("profundidade máxima de recursão atingida", "depth", depth, "folder", n.Path)
+		return fmt.Errorf("profundidade máxima de pastas excedida (%d níveis)", maxDepth)
+	}
+
+	items, err := drive.ListFilesInFolder(ctx, b, folderID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range items {
+		if !f.IsFolder() {
+			n.files = append(n.files, f)
+			n.Files++
+			n.Size += f.Size
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		child := &Node{Name: f.Name, Path: path.Join(n.Path, f.Name)}
+		if err := scan(ctx, b, child, f.ID, depth+1); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			slog.Error("erro ao listar pasta, continuando", "folder", child.Path, "error", err)
+		}
+		n.Children = append(n.Children, child)
+		n.Files += child.Files
+		n.Size += child.Size
+	}
+	return nil
+}
+
+// Prune retorna uma cópia da árvore com no máximo depth níveis de subpastas;
+// os totais continuam incluindo as pastas omitidas. Com depth <= 0 a árvore
+// é mantida inteira.
+func (n *Node) Prune(depth int) *Node {
+	if depth <= 0 {
+		c := *n
+		return &c
+	}
+	return n.prune(depth)
+}
+
+func (n *Node) prune(levels int) *Node {
+	c := *n
+	c.Children = nil
+	if levels == 0 {
+		return &c
+	}
+	for _, child := range n.Children {
+		c.Children = append(c.Children, child.prune(levels-1))
+	}
+	return &c
+}
+
+// Walk chama fn para cada arquivo da árvore, com o caminho da pasta dele.
+func (n *Node) Walk(fn func(f *drive.FileInfo, folder string)) {
+	for _, f := range n.files {
+		fn(f, n.Path)
+	}
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Bucket é a contagem de arquivos e bytes de um grupo.
+type Bucket struct {
+	Key   string `json:"key"`
+	Files int    `json:"files"`
+	Size  int64  `json:"size"`
+}
+
+// Stats resume os arquivos de uma árvore por família de MIME type, por pasta
+// de primeiro nível e por ano de modificação.
+type Stats struct {
+	Files       int      `json:"files"`
+	Size        int64    `json:"size"`
+	Folders     int      `json:"folders"`
+	ByMimeType  []Bucket `json:"by_mime_family"`
+	ByTopFolder []Bucket `json:"by_top_folder"`
+	ByYear      []Bucket `json:"by_year"`
+}
+
+// RootKey agrupa, em ByTopFolder, os arquivos que estão na própria raiz.
+const RootKey = "(raiz)"
+
+// UnknownYear agrupa, em ByYear, os arquivos sem data de modificação.
+const UnknownYear = "desconhecido"
+
+// ComputeStats calcula as estatísticas dos arquivos da árvore. Famílias e
+// pastas saem em ordem decrescente de bytes; anos, em ordem cronológica.
+func ComputeStats(root *Node) Stats {
+	s := Stats{Files: root.Files, Size: root.Size, Folders: countFolders(root)}
+
+	byMime := make(map[string]*Bucket)
+	byTop := make(map[string]*Bucket)
+	byYear := make(map[string]*Bucket)
+	root.Walk(func(f *drive.FileInfo, folder string) {
+		top := RootKey
+		if rel := strings.TrimPrefix(strings.TrimPrefix(folder, root.Path), "/"); rel != "" {
+			top, _, _ = strings.Cut(rel, "/")
+		}
+		year := UnknownYear
+		if len(f.ModifiedTime) >= 4 {
+			year = f.ModifiedTime[:4]
+		}
+
+		add(byMime, MimeFamily(f.MimeType), f.Size)
+		add(byTop, top, f.Size)
+		add(byYear, year, f.Size)
+	})
+
+	s.ByMimeType = sortedBySize(byMime)
+	s.ByTopFolder = sortedBySize(byTop)
+	s.ByYear = sortedByKey(byYear)
+	return s
+}
+
+func countFolders(n *Node) int {
+	count := len(n.Children)
+	for _, child := range n.Children {
+		count += countFolders(child)
+	}
+	return count
+}
+
+func add(m map[string]*Bucket, key string, size int64) {
+	b, ok := m[key]
+	if !ok {
+		b = &Bucket{Key: key}
+		m[key] = b
+	}
+	b.Files++
+	b.Size += size
+}
+
+func sortedBySize(m map[string]*Bucket) []Bucket {
+	buckets := make([]Bucket, 0, len(m))
+	for _, b := range m {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Size != buckets[j].Size {
+			return buckets[i].Size > buckets[j].Size
+		}
+		return buckets[i].Key < buckets[j].Key
+	})
+	return buckets
+}
+
+func sortedByKey(m map[string]*Bucket) []Bucket {
+	buckets := make([]Bucket, 0, len(m))
+	for _, b := range m {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Key < buckets[j].Key })
+	return buckets
+}
+
+// mimeFamilies mapeiam MIME types exatos para famílias; prefixos ficam em
+// MimeFamily.
+var mimeFamilies = map[string]string{
+	"application/pdf":                         "PDF",
+	"application/msword":                      "Documentos",
+	"application/vnd.oasis.opendocument.text": "Documentos",
+	"application/rtf":                         "Documentos",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "Documentos",
+	"application/vnd.google-apps.document":                                    "Documentos",
+	"application/vnd.ms-excel":                                                "Planilhas",
+	"application/vnd.oasis.opendocument.spreadsheet":                          "Planilhas",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       "Planilhas",
+	"application/vnd.google-apps.spreadsheet":                                 "Planilhas",
+	"text/csv":                      "Planilhas",
+	"application/vnd.ms-powerpoint": "Apresentações",
+	"application/vnd.oasis.opendocument.presentation":                           "Apresentações",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "Apresentações",
+	"application/vnd.google-apps.presentation":                                  "Apresentações",
+	"application/zip":              "Compactados",
+	"application/x-zip-compressed": "Compactados",
+	"application/x-rar-compressed": "Compactados",
+	"application/vnd.rar":          "Compactados",
+	"application/x-7z-compressed":  "Compactados",
+	"application/gzip":             "Compactados",
+	"application/x-tar":            "Compactados",
+}
+
+// MimeFamily agrupa um MIME type em uma família legível, como "Imagens" ou
+// "Planilhas".
+func MimeFamily(mimeType string) string {
+	if family, ok := mimeFamilies[mimeType]; ok {
+		return family
+	}
+	switch {
+	case strings.HasPrefix(mimeType, "image/"), mimeType == "application/vnd.google-apps.photo":
+		return "Imagens"
+	case strings.HasPrefix(mimeType, "video/"), mimeType == "application/vnd.google-apps.video":
+		return "Vídeos"
+	case strings.HasPrefix(mimeType, "audio/"), mimeType == "application/vnd.google-apps.audio":
+		return "Áudio"
+	case strings.HasPrefix(mimeType, "text/"):
+		return "Texto"
+	case strings.HasPrefix(mimeType, "application/vnd.google-apps."):
+		return "Outros do Google"
+	}
+	return "Outros"
+}
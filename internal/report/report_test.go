@@ -0,0 +1,137 @@
+package report
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// sampleTree monta:
+//
+//	/            leia-me.txt (10)
+//	Fotos/       a.jpg (100, 2023), 2024/b.png (200, 2024)
+//	Docs/        contrato.pdf (50, 2024), Antigos/Velhos/c.pdf (5, sem data)
+func sampleTree(t *testing.T) *Node {
+	t.Helper()
+	m := drive.NewMemoryBackend()
+	modified := make(map[string]string)
+	file := func(name, mimeType string, size int64, modifiedTime string, parent string) {
+		m.AddFile(name, mimeType, size, parent)
+		modified[name] = modifiedTime
+	}
+
+	file("leia-me.txt", "text/plain", 10, "2024-05-01T00:00:00Z", m.RootID())
+	fotos := m.AddFolder("Fotos")
+	file("a.jpg", "image/jpeg", 100, "2023-02-01T00:00:00Z", fotos.ID)
+	fotos2024 := m.AddFolder("2024", fotos.ID)
+	file("b.png", "image/png", 200, "2024-03-01T00:00:00Z", fotos2024.ID)
+	docs := m.AddFolder("Docs")
+	file("contrato.pdf", "application/pdf", 50, "2024-01-01T00:00:00Z", docs.ID)
+	antigos := m.AddFolder("Antigos", docs.ID)
+	velhos := m.AddFolder("Velhos", antigos.ID)
+	file("c.pdf", "application/pdf", 5, "", velhos.ID)
+
+	root, err := Scan(context.Background(), m, m.RootID(), "")
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	root.Walk(func(f *drive.FileInfo, folder string) { f.ModifiedTime = modified[f.Name] })
+	return root
+}
+
+func TestScanTotals(t *testing.T) {
+	root := sampleTree(t)
+	if root.Name != "/" || root.Files != 5 || root.Size != 365 {
+		t.Fatalf("raiz = %s, %d arquivos, %d bytes; want /, 5, 365", root.Name, root.Files, root.Size)
+	}
+	docs := root.Children[0]
+	if docs.Path != "Docs" || docs.Files != 2 || docs.Size != 55 {
+		t.Errorf("Docs = %s, %d arquivos, %d bytes; want Docs, 2, 55", docs.Path, docs.Files, docs.Size)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	root := sampleTree(t)
+
+	tests := []struct {
+		depth int
+		want  []string // caminhos das pastas mantidas
+	}{
+		{0, []string{"Docs", "Docs/Antigos", "Docs/Antigos/Velhos", "Fotos", "Fotos/2024"}},
+		{1, []string{"Docs", "Fotos"}},
+		{2, []string{"Docs", "Docs/Antigos", "Fotos", "Fotos/2024"}},
+		{5, []string{"Docs", "Docs/Antigos", "Docs/Antigos/Velhos", "Fotos", "Fotos/2024"}},
+	}
+	for _, tt := range tests {
+		pruned := root.Prune(tt.depth)
+		var got []string
+		var walk func(n *Node)
+		walk = func(n *Node) {
+			for _, c := range n.Children {
+				got = append(got, c.Path)
+				walk(c)
+			}
+		}
+		walk(pruned)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prune(%d) = %v, want %v", tt.depth, got, tt.want)
+		}
+		// Os totais continuam contando as pastas omitidas
+		if pruned.Files != 5 || pruned.Children[0].Size != 55 {
+			t.Errorf("Prune(%d) alterou os totais: %d arquivos, Docs com %d bytes", tt.depth, pruned.Files, pruned.Children[0].Size)
+		}
+	}
+
+	if len(root.Children[0].Children) != 1 {
+		t.Error("Prune alterou a árvore original")
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	s := ComputeStats(sampleTree(t))
+
+	if s.Files != 5 || s.Size != 365 || s.Folders != 5 {
+		t.Errorf("totais = %d arquivos, %d bytes, %d pastas; want 5, 365, 5", s.Files, s.Size, s.Folders)
+	}
+	wantMime := []Bucket{{"Imagens", 2, 300}, {"PDF", 2, 55}, {"Texto", 1, 10}}
+	if !reflect.DeepEqual(s.ByMimeType, wantMime) {
+		t.Errorf("ByMimeType = %v, want %v", s.ByMimeType, wantMime)
+	}
+	wantTop := []Bucket{{"Fotos", 2, 300}, {"Docs", 2, 55}, {RootKey, 1, 10}}
+	if !reflect.DeepEqual(s.ByTopFolder, wantTop) {
+		t.Errorf("ByTopFolder = %v, want %v", s.ByTopFolder, wantTop)
+	}
+	wantYear := []Bucket{{"2023", 1, 100}, {"2024", 3, 260}, {UnknownYear, 1, 5}}
+	if !reflect.DeepEqual(s.ByYear, wantYear) {
+		t.Errorf("ByYear = %v, want %v", s.ByYear, wantYear)
+	}
+}
+
+func TestComputeStatsFromSubfolder(t *testing.T) {
+	root := sampleTree(t)
+	s := ComputeStats(root.Children[0])
+
+	wantTop := []Bucket{{RootKey, 1, 50}, {"Antigos", 1, 5}}
+	if !reflect.DeepEqual(s.ByTopFolder, wantTop) {
+		t.Errorf("ByTopFolder = %v, want %v", s.ByTopFolder, wantTop)
+	}
+}
+
+func TestMimeFamily(t *testing.T) {
+	tests := map[string]string{
+		"application/pdf":                  "PDF",
+		"image/heic":                       "Imagens",
+		"video/mp4":                        "Vídeos",
+		"text/csv":                         "Planilhas",
+		"text/markdown":                    "Texto",
+		"application/vnd.google-apps.form": "Outros do Google",
+		"application/octet-stream":         "Outros",
+	}
+	for mimeType, want := range tests {
+		if got := MimeFamily(mimeType); got != want {
+			t.Errorf("MimeFamily(%q) = %s, want %s", mimeType, got, want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+)
+
+//go:embed static
+var static embed.FS
+
+// Server expõe um plano (ou a fila do watch) para revisão no navegador. O
+// plano é relido do disco a cada requisição e gravado a cada alteração, então
+// fechar o navegador ou reiniciar o servidor não perde nada. As alterações são
+// feitas com o plano travado (plan.Lock), o que permite ao watch atualizar a
+// mesma fila enquanto ela é revisada.
+type Server struct {
+	backend  drive.Backend
+	planPath string
+	dryRun   bool
+
+	// folders são as pastas existentes, sugeridas ao editar o destino
+	folders []string
+
+	// mu serializa as requisições: cada uma lê, altera e grava o plano inteiro
+	mu      sync.Mutex
+	applied int
+}
+
+// New cria o servidor do plano em planPath. As movimentações passam por b,
+// que deve ser o mesmo backend do plano.
+func New(b drive.Backend, planPath string, folders []string, dryRun bool) *Server {
+	return &Server{backend: b, planPath: planPath, folders: folders, dryRun: dryRun}
+}
+
+// Applied retorna quantas entradas foram aplicadas desde o início.
+func (s *Server) Applied() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applied
+}
+
+// Handler retorna as rotas da interface e da API.
+func (s *Server) Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(files))
+	mux.HandleFunc("GET /api/entries", s.handleList)
+	mux.HandleFunc("POST /api/entries/{id}", s.handleEdit)
+	mux.HandleFunc("POST /api/entries/{id}/apply", s.handleApply)
+	mux.HandleFunc("POST /api/entries/{id}/skip", s.handleSkip)
+	mux.HandleFunc("POST /api/apply", s.handleApplyApproved)
+	return guard(mux)
+}
+
+// guard recusa requisições de outros sites. Hosts que não sejam um IP ou
+// localhost indicam DNS rebinding; alterações precisam vir em JSON, o que
+// obriga o navegador a pedir permissão (CORS) antes de enviá-las de outra
+// origem.
+func guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "localhost" && net.ParseIP(strings.Trim(host, "[]")) == nil {
+			http.Error(w, "host não permitido", http.StatusForbidden)
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+				http.Error(w, "origem não permitida", http.StatusForbidden)
+				return
+			}
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+				http.Error(w, "use Content-Type: application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type listResponse struct {
+	Backend string       `json:"backend"`
+	Model   string       `json:"model,omitempty"`
+	DryRun  bool         `json:"dry_run"`
+	Entries []plan.Entry `json:"entries"`
+	Folders []string     `json:"folders"`
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := plan.Load(s.planPath)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	folders := slices.Clone(s.folders)
+	for _, e := range p.Entries {
+		if e.TargetFolder != "" {
+			folders = append(folders, e.TargetFolder)
+		}
+	}
+	slices.Sort(folders)
+
+	writeJSON(w, http.StatusOK, listResponse{
+		Backend: p.Backend,
+		Model:   p.Model,
+		DryRun:  s.dryRun,
+		Entries: append([]plan.Entry{}, p.Entries...),
+		Folders: slices.Compact(folders),
+	})
+}
+
+// editRequest altera uma entrada; campos ausentes ficam como estão.
+type editRequest struct {
+	TargetFolder *string `json:"target_folder"`
+	TargetName   *string `json:"target_name"`
+	Approved     *bool   `json:"approved"`
+}
+
+// apply valida e aplica a edição em e.
+func (req editRequest) apply(e *plan.Entry) error {
+	if req.TargetFolder != nil {
+		e.TargetFolder = strings.Trim(strings.TrimSpace(*req.TargetFolder), "/")
+	}
+	if req.TargetName != nil {
+		e.TargetName = strings.TrimSpace(*req.TargetName)
+	}
+	if req.Approved != nil {
+		e.Approved = *req.Approved
+	}
+
+	if strings.Contains(e.TargetName, "/") {
+		return fmt.Errorf("o nome do arquivo não pode conter '/'")
+	}
+	if e.Approved && e.TargetFolder == "" {
+		return fmt.Errorf("defina a pasta de destino de %s", e.CurrentPath)
+	}
+	return nil
+}
+
+func (s *Server) handleEdit(w http.ResponseWriter, r *http.Request) {
+	var req editRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := plan.Lock(s.planPath)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer unlock()
+
+	p, e, err := s.loadEntry(r.PathValue("id"))
+	if err != nil {
+		writeEntryError(w, err)
+		return
+	}
+	if err := req.apply(e); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := p.Save(s.planPath); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e)
+}
+
+// applyResult é o resultado da aplicação de uma entrada.
+type applyResult struct {
+	FileID      string `json:"file_id"`
+	CurrentPath string `json:"current_path"`
+	TargetPath  string `json:"target_path"`
+	Error       string `json:"error,omitempty"`
+	Stale       bool   `json:"stale,omitempty"`
+}
+
+// handleApply aplica uma entrada, com as edições enviadas no corpo. Aplicada,
+// a entrada sai do plano.
+func (s *Server) handleApply(w http.ResponseWriter, r *http.Request) {
+	var req editRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := plan.Lock(s.planPath)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer unlock()
+
+	p, e, err := s.loadEntry(r.PathValue("id"))
+	if err != nil {
+		writeEntryError(w, err)
+		return
+	}
+	if err := req.apply(e); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if e.TargetFolder == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("defina a pasta de destino de %s", e.CurrentPath))
+		return
+	}
+
+	res := s.applyEntry(r, p, *e)
+	if err := p.Save(s.planPath); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	status := http.StatusOK
+	switch {
+	case res.Stale:
+		status = http.StatusConflict
+	case res.Error != "":
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, res)
+}
+
+// handleSkip tira a entrada do plano; o arquivo fica onde está.
+func (s *Server) handleSkip(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := plan.Lock(s.planPath)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	defer unlock()
+
+	p, e, err := s.loadEntry(r.PathValue("id"))
+	if err != nil {
+		writeEntryError(w, err)
+		return
+	}
+	p.Remove(e.FileID)
+	if err := p.Save(s.planPath); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type bulkResponse struct {
+	Applied int           `json:"applied"`
+	Results []applyResult `json:"results"`
+}
+
+// handleApplyApproved aplica todas as entradas aprovadas, uma a uma. Cada
+// entrada é aplicada e gravada com o plano travado e relido, para que uma
+// interrupção não perca as já aplicadas e o watch possa atualizar a fila no
+// meio do caminho.
+func (s *Server) handleApplyApproved(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := plan.Load(s.planPath)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := bulkResponse{Results: []applyResult{}}
+	for _, e := range p.Entries {
+		if !e.Approved {
+			continue
+		}
+		if r.Context().Err() != nil {
+			break
+		}
+
+		res, err := s.applyApproved(r, e.FileID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if res == nil {
+			continue
+		}
+		if res.Error == "" {
+			resp.Applied++
+		}
+		resp.Results = append(resp.Results, *res)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// applyApproved trava e relê o plano e aplica a entrada de fileID se ela
+// continuar aprovada. Sem nada a aplicar (a entrada saiu do plano ou perdeu a
+// aprovação), o resultado é nil.
+func (s *Server) applyApproved(r *http.Request, fileID string) (*applyResult, error) {
+	unlock, err := plan.Lock(s.planPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	p, e, err := s.loadEntry(fileID)
+	if errors.Is(err, errNotInPlan) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !e.Approved {
+		return nil, nil
+	}
+
+	res := s.applyEntry(r, p, *e)
+	if res.Error == "" {
+		if err := p.Save(s.planPath); err != nil {
+			return nil, err
+		}
+	}
+	return &res, nil
+}
+
+// applyEntry move o arquivo de e e, se der certo, tira a entrada de p. Em
+// dry-run, nada muda.
+func (s *Server) applyEntry(r *http.Request, p *plan.Plan, e plan.Entry) applyResult {
+	res := applyResult{FileID: e.FileID, CurrentPath: e.CurrentPath, TargetPath: e.TargetPath()}
+
+	err := e.Apply(r.Context(), s.backend, s.dryRun)
+	switch {
+	case errors.Is(err, plan.ErrStale):
+		res.Error = err.Error()
+		res.Stale = true
+	case err != nil:
+		slog.Error("falha ao aplicar entrada do plano", "file", e.CurrentPath, "error", err)
+		res.Error = err.Error()
+	case s.dryRun:
+		slog.Info("dry-run: arquivo seria movido", "file", e.CurrentPath, "target", res.TargetPath)
+	default:
+		slog.Info("arquivo movido", "file", e.CurrentPath, "target", res.TargetPath)
+		p.Remove(e.FileID)
+		s.applied++
+	}
+	return res
+}
+
+// errNotInPlan indica uma entrada que não está (mais) no plano.
+var errNotInPlan = errors.New("arquivo não está no plano")
+
+// loadEntry relê o plano e retorna a entrada do arquivo fileID.
+func (s *Server) loadEntry(fileID string) (*plan.Plan, *plan.Entry, error) {
+	p, err := plan.Load(s.planPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range p.Entries {
+		if p.Entries[i].FileID == fileID {
+			return p, &p.Entries[i], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s", errNotInPlan, fileID)
+}
+
+func writeEntryError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, errNotInPlan) {
+		status = http.StatusNotFound
+	}
+	writeError(w, status, err)
+}
+
+func decode(r *http.Request, v any) error {
+	err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("requisição inválida: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("erro ao enviar resposta", "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
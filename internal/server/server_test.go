@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+	"github.com/vitoramaral10/driver-organizer/internal/plan"
+)
+
+// newTestServer grava um plano com uma entrada por arquivo de files, todas
+// com destino Financeiro e sem aprovação.
+func newTestServer(t *testing.T, m *drive.MemoryBackend, files ...*drive.FileInfo) (*Server, string) {
+	t.Helper()
+	p := &plan.Plan{Version: plan.Version, Backend: "memory"}
+	for _, f := range files {
+		e := plan.NewEntry(f, f.Name)
+		e.TargetFolder = "Financeiro"
+		p.Entries = append(p.Entries, e)
+	}
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(planPath); err != nil {
+		t.Fatal(err)
+	}
+	return New(m, planPath, []string{"Documentos"}, false), planPath
+}
+
+func do(t *testing.T, h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Host = "127.0.0.1:8080"
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func loadPlan(t *testing.T, planPath string) *plan.Plan {
+	t.Helper()
+	p, err := plan.Load(planPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return p
+}
+
+func TestServerEditApplyAndSkip(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	boleto := m.AddFile("boleto.pdf", "application/pdf", 10)
+	fatura := m.AddFile("fatura.pdf", "application/pdf", 10)
+	extrato := m.AddFile("extrato.pdf", "application/pdf", 10)
+	s, planPath := newTestServer(t, m, boleto, fatura, extrato)
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodGet, "/api/entries", "")
+	var list listResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list.Entries) != 3 {
+		t.Fatalf("GET /api/entries = %d %s", rec.Code, rec.Body)
+	}
+	if strings.Join(list.Folders, ",") != "Documentos,Financeiro" {
+		t.Errorf("pastas = %v", list.Folders)
+	}
+
+	// Edição: nova pasta e aprovação, gravadas no plano
+	rec = do(t, h, http.MethodPost, "/api/entries/"+boleto.ID, `{"target_folder": "/Financeiro/Boletos/", "approved": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("edição = %d %s", rec.Code, rec.Body)
+	}
+	if e := loadPlan(t, planPath).Entries[0]; e.TargetFolder != "Financeiro/Boletos" || !e.Approved {
+		t.Errorf("entrada editada = %+v", e)
+	}
+	if rec := do(t, h, http.MethodPost, "/api/entries/"+boleto.ID, `{"target_name": "a/b.pdf"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("nome com '/' = %d, want 400", rec.Code)
+	}
+
+	// Aplicação de uma entrada com edição no corpo
+	rec = do(t, h, http.MethodPost, "/api/entries/"+fatura.ID+"/apply", `{"target_name": "Fatura Jan.pdf"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("apply = %d %s", rec.Code, rec.Body)
+	}
+	moved, _ := m.GetFile(context.Background(), fatura.ID)
+	if moved.Name != "Fatura Jan.pdf" || moved.Parents[0] == m.RootID() {
+		t.Errorf("arquivo aplicado = %s em %v", moved.Name, moved.Parents)
+	}
+
+	// Pular tira a entrada sem mexer no arquivo
+	if rec := do(t, h, http.MethodPost, "/api/entries/"+extrato.ID+"/skip", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("skip = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, h, http.MethodPost, "/api/entries/"+extrato.ID+"/skip", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("skip repetido = %d, want 404", rec.Code)
+	}
+
+	p := loadPlan(t, planPath)
+	if len(p.Entries) != 1 || p.Entries[0].FileID != boleto.ID {
+		t.Errorf("plano = %+v, want só o boleto", p.Entries)
+	}
+
+	// Aplicar as aprovadas move o boleto e esvazia o plano
+	rec = do(t, h, http.MethodPost, "/api/apply", "")
+	var bulk bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &bulk); err != nil || bulk.Applied != 1 {
+		t.Fatalf("apply das aprovadas = %d %s", rec.Code, rec.Body)
+	}
+	if len(loadPlan(t, planPath).Entries) != 0 || s.Applied() != 2 {
+		t.Errorf("plano com %d entradas, %d aplicadas", len(loadPlan(t, planPath).Entries), s.Applied())
+	}
+}
+
+func TestServerApplyStale(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	f := m.AddFile("boleto.pdf", "application/pdf", 10)
+	s, planPath := newTestServer(t, m, f)
+
+	// Renomeado fora do plano
+	m.RenameFile(context.Background(), f.ID, "outro.pdf")
+
+	rec := do(t, s.Handler(), http.MethodPost, "/api/entries/"+f.ID+"/apply", "")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("apply de entrada desatualizada = %d %s, want 409", rec.Code, rec.Body)
+	}
+	if len(loadPlan(t, planPath).Entries) != 1 {
+		t.Error("entrada desatualizada saiu do plano")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	m := drive.NewMemoryBackend()
+	s, _ := newTestServer(t, m)
+	h := s.Handler()
+
+	tests := []struct {
+		name        string
+		host        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{"localhost", "localhost:8080", "", "application/json", http.StatusOK},
+		{"DNS rebinding", "evil.example:8080", "", "application/json", http.StatusForbidden},
+		{"outra origem", "127.0.0.1:8080", "http://evil.example", "application/json", http.StatusForbidden},
+		{"formulário", "127.0.0.1:8080", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/apply", nil)
+		req.Host = tt.host
+		req.Header.Set("Content-Type", tt.contentType)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+// State guarda, por backend, o token da API de mudanças até onde as
+// alterações já foram processadas, para que o watch continue de onde parou
+// depois de reiniciado.
+type State struct {
+	path   string
+	Tokens map[string]string `json:"tokens"`
+
+	// ReadOnly faz SetToken atualizar o token só em memória (dry-run).
+	ReadOnly bool `json:"-"`
+}
+
+// LoadState lê o estado salvo em path; um arquivo inexistente resulta em um
+// estado vazio.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Tokens: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler estado do watch: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar estado do watch '%s': %w", path, err)
+	}
+	if s.Tokens == nil {
+		s.Tokens = make(map[string]string)
+	}
+	return s, nil
+}
+
+// Token retorna o token salvo para o backend key, ou "" se ainda não houver.
+func (s *State) Token(key string) string {
+	return s.Tokens[key]
+}
+
+// SetToken atualiza o token do backend key e grava o estado em disco.
+func (s *State) SetToken(key, token string) error {
+	s.Tokens[key] = token
+	if s.ReadOnly {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar estado do watch: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório do estado do watch: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("erro ao salvar estado do watch: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("erro ao salvar estado do watch: %w", err)
+	}
+	return nil
+}
+
+// Batch é uma página de alterações já filtrada para a pasta observada.
+type Batch struct {
+	// Files são os arquivos novos ou alterados na pasta observada.
+	Files []*drive.FileInfo
+
+	// Left são os IDs dos itens acompanhados que não estão mais na pasta
+	// observada: movidos, apagados ou na lixeira.
+	Left []string
+}
+
+// Init salva o token inicial do backend key se ainda não houver um. Retorna
+// true quando o token foi criado agora: só alterações posteriores serão
+// reportadas.
+func Init(ctx context.Context, src drive.ChangeSource, st *State, key string) (bool, error) {
+	if st.Token(key) != "" {
+		return false, nil
+	}
+	token, err := src.StartPageToken(ctx)
+	if err != nil {
+		return false, err
+	}
+	return true, st.SetToken(key, token)
+}
+
+// Poll consulta as alterações desde o token salvo e chama handle com cada
+// página, filtrada para a pasta folderID. Itens que saíram da pasta só entram
+// em Batch.Left se tracked os reconhecer (ex: se estão na fila de revisão);
+// alterações no resto do Drive são ignoradas. O token avança e é gravado só
+// depois que handle retorna sem erro, de modo que nenhuma página seja perdida;
+// se handle falhar, a mesma página é entregue de novo na próxima consulta.
+// Retorna o número de arquivos passados para handle.
+func Poll(ctx context.Context, src drive.ChangeSource, st *State, key string, folderID string, tracked func(fileID string) bool, handle func(ctx context.Context, b Batch) error) (int, error) {
+	token := st.Token(key)
+	if token == "" {
+		return 0, fmt.Errorf("watch não inicializado para %s", key)
+	}
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		changes, next, newStart, err := src.ChangesPage(ctx, token)
+		if err != nil {
+			return total, err
+		}
+
+		batch := filter(changes, folderID, tracked)
+		if len(batch.Files) > 0 || len(batch.Left) > 0 {
+			if err := handle(ctx, batch); err != nil {
+				return total, err
+			}
+		}
+		total += len(batch.Files)
+
+		if next == "" {
+			next = newStart
+		}
+		if next == "" {
+			return total, fmt.Errorf("erro ao listar mudanças: resposta sem token")
+		}
+		if err := st.SetToken(key, next); err != nil {
+			return total, err
+		}
+		token = next
+
+		if newStart != "" {
+			return total, nil
+		}
+	}
+}
+
+// filter separa as alterações de uma página em arquivos a classificar e itens
+// acompanhados (tracked) que saíram da pasta observada. Se um item aparece
+// mais de uma vez na página, vale a última alteração.
+func filter(changes []drive.Change, folderID string, tracked func(fileID string) bool) Batch {
+	last := make(map[string]int, len(changes))
+	for i, c := range changes {
+		last[c.FileID] = i
+	}
+
+	var b Batch
+	for i, c := range changes {
+		if last[c.FileID] != i {
+			continue
+		}
+		f := c.File
+		if c.Removed || f == nil || f.Trashed || !slices.Contains(f.Parents, folderID) {
+			if tracked(c.FileID) {
+				b.Left = append(b.Left, c.FileID)
+			}
+			continue
+		}
+		if f.IsFolder() || !f.CanMove() {
+			continue
+		}
+		b.Files = append(b.Files, f)
+	}
+	return b
+}
@@ -0,0 +1,31 @@
+package watch
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vitoramaral10/driver-organizer/internal/drive"
+)
+
+func TestFilterLeftOnlyTracked(t *testing.T) {
+	file := func(id string, parents ...string) *drive.FileInfo {
+		return &drive.FileInfo{ID: id, Name: id + ".pdf", MimeType: "application/pdf", Parents: parents}
+	}
+	changes := []drive.Change{
+		{FileID: "novo", File: file("novo", "inbox")},
+		{FileID: "na-fila", File: file("na-fila", "Financeiro")},
+		{FileID: "outro", File: file("outro", "Trabalho")},
+		{FileID: "apagado", Removed: true},
+		{FileID: "apagado-na-fila", Removed: true},
+	}
+	tracked := func(id string) bool { return id == "na-fila" || id == "apagado-na-fila" }
+
+	b := filter(changes, "inbox", tracked)
+
+	if len(b.Files) != 1 || b.Files[0].ID != "novo" {
+		t.Errorf("Files = %v, want [novo]", b.Files)
+	}
+	if want := []string{"na-fila", "apagado-na-fila"}; !slices.Equal(b.Left, want) {
+		t.Errorf("Left = %v, want %v", b.Left, want)
+	}
+}